@@ -0,0 +1,16 @@
+//go:build brotli
+
+package httpclient
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Brotli support is optional, as it requires an additional dependency.
+func init() {
+	contentDecoders["br"] = func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(brotli.NewReader(r)), nil
+	}
+}
@@ -0,0 +1,39 @@
+//go:build brotli
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrotliCompressionNegotiation(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := brotli.NewWriter(&compressed)
+	_, _ = writer.Write([]byte(`{"key":"br"}`))
+	require.Nil(t, writer.Close())
+	acceptEncodings := make(chan string, 1)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncodings <- r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(compressed.Bytes())
+	}))
+	defer svr.Close()
+	client := New(WithCompressionNegotiation())
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, svr.URL, nil)
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var output dummyType
+	_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Nil(t, err)
+	require.Equal(t, "br, gzip", <-acceptEncodings)
+	require.Equal(t, "br", output.Key)
+}
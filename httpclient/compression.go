@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedContentEncoding is returned when a response is compressed
+// with a content encoding that cannot be decompressed.
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+// contentDecoders decompress the response bodies by content encoding.
+// Brotli is only registered when built with the "brotli" tag.
+var contentDecoders = map[string]func(r io.Reader) (io.ReadCloser, error){
+	"gzip": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+}
+
+// acceptEncoding returns the value of the Accept-Encoding
+// header listing the supported content encodings.
+func acceptEncoding() string {
+	encodings := make([]string, 0, len(contentDecoders))
+	for encoding := range contentDecoders {
+		encodings = append(encodings, encoding)
+	}
+	sort.Strings(encodings)
+	return strings.Join(encodings, ", ")
+}
+
+// decompressingBody decompresses a response body on the first
+// read, so that empty bodies (e.g. of HEAD requests) can be closed
+// without being decompressed.
+type decompressingBody struct {
+	body   io.ReadCloser
+	decode func(r io.Reader) (io.ReadCloser, error)
+	reader io.ReadCloser
+	err    error
+}
+
+func (b *decompressingBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = b.decode(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+func (b *decompressingBody) Close() error {
+	if b.reader != nil {
+		_ = b.reader.Close()
+	}
+	return b.body.Close()
+}
+
+// decompressingTransport negotiates the content encoding of the
+// responses and decompresses them, falling back to identity when
+// the server does not compress them.
+type decompressingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction. It implements the http.RoundTripper interface.
+func (t *decompressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", acceptEncoding())
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return resp, nil
+	}
+	decode, ok := contentDecoders[encoding]
+	if !ok {
+		closeBody(resp)
+		return nil, errors.Wrapf(ErrUnsupportedContentEncoding, "%q", encoding)
+	}
+	resp.Body = &decompressingBody{body: resp.Body, decode: decode}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
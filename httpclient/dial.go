@@ -0,0 +1,150 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// dualStackFallbackDelay is the default time given to a connection over
+// the preferred IP family before racing one over the other family.
+const dualStackFallbackDelay = 300 * time.Millisecond
+
+// addrsDialFunc dials one of the resolved addresses of a host.
+type addrsDialFunc func(ctx context.Context, network string, addrs []string, port string) (net.Conn, error)
+
+// serialDial returns an addrsDialFunc dialing the addresses in turn
+// through dial, until one of them connects.
+func serialDial(dial dialFunc) addrsDialFunc {
+	return func(ctx context.Context, network string, addrs []string, port string) (net.Conn, error) {
+		var err error
+		for _, addr := range addrs {
+			var conn net.Conn
+			conn, err = dial(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// happyEyeballs dials hosts reachable over both IPv4 and IPv6 with
+// Happy Eyeballs (RFC 8305): the addresses of the family of the first
+// resolved address are dialed in turn, and when none connected within
+// the fallback delay, or as soon as they all failed, the addresses of
+// the other family are dialed concurrently. The first established
+// connection is used, and the other one, if any, is closed.
+type happyEyeballs struct {
+	fallbackDelay time.Duration
+	dial          dialFunc
+}
+
+// newHappyEyeballs creates a Happy Eyeballs dialer dialing each address
+// through dial, waiting fallbackDelay, or dualStackFallbackDelay if not
+// positive, before racing the other family.
+func newHappyEyeballs(fallbackDelay time.Duration, dial dialFunc) *happyEyeballs {
+	if fallbackDelay <= 0 {
+		fallbackDelay = dualStackFallbackDelay
+	}
+	return &happyEyeballs{fallbackDelay: fallbackDelay, dial: dial}
+}
+
+// DialContext resolves the host and races its IPv4 and IPv6 addresses.
+func (h *happyEyeballs) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return h.dial(ctx, network, address)
+	}
+	addrs, err := lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	return h.dialAddrs(ctx, network, addrs, port)
+}
+
+// dialResult is the outcome of dialing the addresses of one family.
+type dialResult struct {
+	conn    net.Conn
+	err     error
+	primary bool
+}
+
+// dialAddrs races the addresses of both families. It implements addrsDialFunc.
+func (h *happyEyeballs) dialAddrs(ctx context.Context, network string, addrs []string, port string) (net.Conn, error) {
+	primary, fallback := splitByFamily(addrs)
+	if network != "tcp" || len(fallback) == 0 {
+		return serialDial(h.dial)(ctx, network, addrs, port)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan dialResult)
+	race := func(addrs []string, primary bool) {
+		conn, err := serialDial(h.dial)(ctx, network, addrs, port)
+		results <- dialResult{conn: conn, err: err, primary: primary}
+	}
+	go race(primary, true)
+	pending := 1
+	fallbackTimer := time.NewTimer(h.fallbackDelay)
+	defer fallbackTimer.Stop()
+	startFallback := func() {
+		if fallback != nil {
+			go race(fallback, false)
+			fallback = nil
+			pending++
+		}
+	}
+	var primaryErr, fallbackErr error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			startFallback()
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// Closes the connection of the losing family, if any.
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if result := <-results; result.conn != nil {
+							result.conn.Close()
+						}
+					}
+				}(pending)
+				return result.conn, nil
+			}
+			if result.primary {
+				primaryErr = result.err
+			} else {
+				fallbackErr = result.err
+			}
+			startFallback()
+			if pending == 0 {
+				if primaryErr != nil {
+					return nil, primaryErr
+				}
+				return nil, fallbackErr
+			}
+		}
+	}
+}
+
+// splitByFamily splits the addresses into those of the family of the
+// first one, preferred, and those of the other family.
+func splitByFamily(addrs []string) (primary, fallback []string) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	isIPv4 := func(addr string) bool {
+		ip := net.ParseIP(addr)
+		return ip != nil && ip.To4() != nil
+	}
+	primaryIPv4 := isIPv4(addrs[0])
+	for _, addr := range addrs {
+		if isIPv4(addr) == primaryIPv4 {
+			primary = append(primary, addr)
+		} else {
+			fallback = append(fallback, addr)
+		}
+	}
+	return primary, fallback
+}
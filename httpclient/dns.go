@@ -0,0 +1,90 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// For ease of unit testing.
+// Declaring this function as a global variable
+// makes it easy to mock it.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// dialFunc dials a network address.
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// dnsCacheEntry holds the resolved addresses of a host.
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache is a dialer resolving each host at most once per TTL.
+type dnsCache struct {
+	ttl       time.Duration
+	dial      dialFunc
+	dialAddrs addrsDialFunc
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+// newDNSCache creates a DNS cache dialing through dial,
+// trying the cached addresses of a host in turn.
+func newDNSCache(ttl time.Duration, dial dialFunc) *dnsCache {
+	return &dnsCache{
+		ttl:       ttl,
+		dial:      dial,
+		dialAddrs: serialDial(dial),
+		entries:   make(map[string]dnsCacheEntry),
+	}
+}
+
+// lookup returns the cached addresses of the host, resolving
+// them again if they are missing or expired.
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+	addrs, err := lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// invalidate drops the cached addresses of the host.
+func (c *dnsCache) invalidate(host string) {
+	c.mu.Lock()
+	delete(c.entries, host)
+	c.mu.Unlock()
+}
+
+// DialContext dials the cached addresses of the host, in turn unless
+// raced by Happy Eyeballs. When none of them can be dialed, they are
+// dropped from the cache, so that the host is resolved again on the
+// next dial.
+func (c *dnsCache) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return c.dial(ctx, network, address)
+	}
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.dialAddrs(ctx, network, addrs, port)
+	if err != nil {
+		c.invalidate(host)
+		return nil, err
+	}
+	return conn, nil
+}
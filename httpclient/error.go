@@ -25,6 +25,11 @@ func (e *HttpError) Error() string {
 		"error: [ %v ]", e.Url, httpStatusCode, e.Body, e.Err)
 }
 
+// Unwrap returns the underlying error, if any.
+func (e *HttpError) Unwrap() error {
+	return e.Err
+}
+
 // sameStatusCodes checks whether status codes are
 // equal, if `anotherStatus` is greater than zero.
 func sameStatusCodes(status, anotherStatus int) bool {
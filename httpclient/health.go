@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The default number of consecutive failures putting a host in cool-down,
+// and the default cool-down.
+const (
+	defaultMaxHostFailures = 3
+	defaultHostCooldown    = 30 * time.Second
+)
+
+// hostHealth tracks the consecutive failures of each host, and skips
+// the hosts that failed too many times in a row for a cool-down,
+// routing their requests to the first healthy one of the hosts.
+type hostHealth struct {
+	maxFailures int
+	cooldown    time.Duration
+	// hosts are the scheme and host of the interchangeable
+	// endpoints, in order of preference.
+	hosts []*url.URL
+
+	mu        sync.Mutex
+	failures  map[string]int
+	downUntil map[string]time.Time
+}
+
+// newHostHealth creates a host health tracker for the base URLs,
+// skipping malformed ones, or nil if none is left. Non-positive
+// maxFailures and cooldown are replaced with the defaults.
+func newHostHealth(maxFailures int, cooldown time.Duration, baseUrls []string) *hostHealth {
+	var hosts []*url.URL
+	for _, baseUrl := range baseUrls {
+		u, err := url.Parse(baseUrl)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		hosts = append(hosts, &url.URL{Scheme: u.Scheme, Host: strings.ToLower(u.Host)})
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	if maxFailures < 1 {
+		maxFailures = defaultMaxHostFailures
+	}
+	if cooldown <= 0 {
+		cooldown = defaultHostCooldown
+	}
+	return &hostHealth{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		hosts:       hosts,
+		failures:    make(map[string]int),
+		downUntil:   make(map[string]time.Time),
+	}
+}
+
+// healthy reports whether the host is not cooling down. It must
+// be called with the mutex held.
+func (h *hostHealth) healthy(host string, now time.Time) bool {
+	return !now.Before(h.downUntil[host])
+}
+
+// route returns the request to send instead of req: req itself if its
+// host is healthy or not tracked, or a copy sent to the first healthy
+// host otherwise. When every host is cooling down, req is sent as is.
+func (h *hostHealth) route(req *http.Request) *http.Request {
+	host := strings.ToLower(req.URL.Host)
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.healthy(host, now) || !h.tracks(host) {
+		return req
+	}
+	for _, u := range h.hosts {
+		if !h.healthy(u.Host, now) {
+			continue
+		}
+		routed := req.Clone(req.Context())
+		routed.URL.Scheme, routed.URL.Host = u.Scheme, u.Host
+		routed.Host = ""
+		return routed
+	}
+	return req
+}
+
+// tracks reports whether the host is one of the tracked hosts.
+func (h *hostHealth) tracks(host string) bool {
+	for _, u := range h.hosts {
+		if u.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// observe counts a failure of the host, an error or a 5xx response, and
+// puts the host in cool-down after too many of them in a row. Any other
+// response resets its count. Canceled requests and the requests to the
+// hosts that are not tracked are not counted.
+func (h *hostHealth) observe(req *http.Request, resp *http.Response, err error) {
+	if err != nil && req.Context().Err() != nil {
+		return
+	}
+	host := strings.ToLower(req.URL.Host)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.tracks(host) {
+		return
+	}
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		delete(h.failures, host)
+		return
+	}
+	h.failures[host]++
+	if h.failures[host] >= h.maxFailures {
+		delete(h.failures, host)
+		h.downUntil[host] = time.Now().Add(h.cooldown)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"mime"
+	"net"
 	"net/http"
 	"net/http/httputil"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -36,6 +39,7 @@ var (
 			}
 		}
 		if receivedError != nil {
+			closeBody(resp)
 			return &HttpError{
 				Url: url,
 				Err: receivedError,
@@ -47,6 +51,9 @@ var (
 		if v != nil {
 			if resp != nil {
 				defer resp.Body.Close()
+				if err := checkContentType(url, resp); err != nil {
+					return err
+				}
 				if err := jsonDecode(resp.Body, v); err != nil {
 					return &HttpError{
 						Url:        url,
@@ -54,6 +61,9 @@ var (
 						Err:        errors.Wrap(err, "decoding response"),
 					}
 				}
+				// Reads the body up to its end, so that
+				// the trailers, if any, are received.
+				_, _ = io.Copy(io.Discard, resp.Body)
 			}
 		}
 		return nil
@@ -67,6 +77,51 @@ var (
 	dumpRequestOut = httputil.DumpRequestOut
 )
 
+// closeBody closes the response body, if there is a response.
+func closeBody(resp *http.Response) {
+	if resp != nil {
+		resp.Body.Close()
+	}
+}
+
+// bodySnippetLength is the maximum length of the response
+// body included in an unexpected content type error.
+const bodySnippetLength = 512
+
+// ErrUnexpectedContentType is returned when a successful response
+// that was expected to be JSON has a different content type, such
+// as an HTML error page served by a misconfigured proxy.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// isJsonContentType checks whether the content type is JSON.
+// A missing content type is assumed to be JSON.
+func isJsonContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// checkContentType returns an *HttpError wrapping ErrUnexpectedContentType,
+// along with a snippet of the body, if the response is not JSON.
+func checkContentType(url string, resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if isJsonContentType(contentType) {
+		return nil
+	}
+	snippet, _ := ioReadAll(io.LimitReader(resp.Body, bodySnippetLength))
+	return &HttpError{
+		Url:        url,
+		StatusCode: resp.StatusCode,
+		Body:       string(snippet),
+		Err:        errors.Wrapf(ErrUnexpectedContentType, "%q", contentType),
+	}
+}
+
 // Client defines the interface for an HTTP client that can send requests.
 type Client interface {
 	// SendRequest sends an HTTP request and returns the response.
@@ -89,6 +144,20 @@ type client struct {
 	retryWaitMax        time.Duration
 	requestDumpLogger   func(dump []byte)
 	dumpRequestBody     bool
+	dnsCacheTTL         time.Duration
+	dualStackDial       bool
+	dualStackFallback   time.Duration
+	maxConnLifetime     time.Duration
+	perAttemptTimeout   time.Duration
+	compression         bool
+	retryReasonLog      func(attempt int, reason string)
+	bodyReadIdleTimeout time.Duration
+	idleConnTimeout     time.Duration
+	perHostPools        bool
+	timeoutsByPolicy    bool
+	requestTimeout      time.Duration
+	requestHook         func(req *http.Request, attempt int)
+	hostHealth          *hostHealth
 }
 
 // This construct aids in mocking by allowing users to implement only
@@ -106,16 +175,96 @@ func doNotRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool
 	return false, nil
 }
 
+// retryTimedOutAttempts wraps the retry policy so that the attempts
+// exceeding the per-attempt timeout are retried, as long as the context
+// of the request is not done. Other attempts are left to the policy.
+func retryTimedOutAttempts(checkRetryPolicy retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	if checkRetryPolicy == nil {
+		checkRetryPolicy = doNotRetryPolicy
+	}
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		var netErr net.Error
+		if ctx.Err() == nil && errors.As(err, &netErr) && netErr.Timeout() {
+			return true, err
+		}
+		return checkRetryPolicy(ctx, resp, err)
+	}
+}
+
+// configureTransport applies the transport options.
+func (c *client) configureTransport(transport *http.Transport) {
+	var eyeballs *happyEyeballs
+	if c.dualStackDial {
+		eyeballs = newHappyEyeballs(c.dualStackFallback, transport.DialContext)
+	}
+	if c.dnsCacheTTL > 0 {
+		cache := newDNSCache(c.dnsCacheTTL, transport.DialContext)
+		if eyeballs != nil {
+			cache.dialAddrs = eyeballs.dialAddrs
+		}
+		transport.DialContext = cache.DialContext
+	} else if eyeballs != nil {
+		transport.DialContext = eyeballs.DialContext
+	}
+	if c.maxConnLifetime > 0 {
+		transport.DialContext = maxConnLifetimeDial(c.maxConnLifetime, transport.DialContext)
+	}
+	if c.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = c.idleConnTimeout
+	}
+	if c.maxIdleConns > 0 {
+		transport.MaxIdleConns = c.maxIdleConns
+	}
+	if c.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.maxIdleConnsPerHost
+	}
+	if c.maxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = c.maxConnsPerHost
+	}
+}
+
 // patchRetryableClient patches retryable http client.
 func patchRetryableClient(c *client) {
 	c.retryableHttpClient.SetRetryMax(c.maxRetries)
 	c.retryableHttpClient.SetRetryWaitMin(c.retryWaitMin)
 	c.retryableHttpClient.SetRetryWaitMax(c.retryWaitMax)
+	c.retryableHttpClient.ConfigureTransport(c.configureTransport)
+	// Wraps the configured transport first, so that the clones
+	// honor its configuration and the other wrappers apply to all.
+	if c.perHostPools || c.hostHealth != nil {
+		c.retryableHttpClient.WrapTransport(func(transport http.RoundTripper) http.RoundTripper {
+			return newPerHostTransport(transport, c.hostHealth)
+		})
+	}
+	if c.bodyReadIdleTimeout > 0 {
+		c.retryableHttpClient.WrapTransport(func(transport http.RoundTripper) http.RoundTripper {
+			return &idleTimeoutTransport{next: transport, timeout: c.bodyReadIdleTimeout}
+		})
+	}
+	// Wraps the idle timeout, if any, so that it applies to compressed bytes.
+	if c.compression {
+		c.retryableHttpClient.WrapTransport(func(transport http.RoundTripper) http.RoundTripper {
+			return &decompressingTransport{next: transport}
+		})
+	}
 	// If no custom check retry policy is provided,
 	// doNotRetryPolicy will be used.
-	c.retryableHttpClient.SetCheckRetry(doNotRetryPolicy)
+	checkRetryPolicy := retryablehttp.CheckRetry(doNotRetryPolicy)
 	if c.checkRetryPolicy != nil {
-		c.retryableHttpClient.SetCheckRetry(c.checkRetryPolicy)
+		checkRetryPolicy = c.checkRetryPolicy
+	}
+	if c.perAttemptTimeout > 0 {
+		c.retryableHttpClient.SetAttemptTimeout(c.perAttemptTimeout)
+		if !c.timeoutsByPolicy {
+			checkRetryPolicy = retryTimedOutAttempts(c.checkRetryPolicy)
+		}
+	}
+	if c.retryReasonLog != nil {
+		checkRetryPolicy = logRetryReasons(checkRetryPolicy, c.maxRetries, c.retryReasonLog)
+	}
+	c.retryableHttpClient.SetCheckRetry(checkRetryPolicy)
+	if c.requestHook != nil {
+		c.retryableHttpClient.SetRequestHook(c.requestHook)
 	}
 }
 
@@ -137,8 +286,12 @@ func New(options ...Option) Client {
 }
 
 // do performs a request and parses the response to the given interface, if provided.
+// The wait between retries, including the one requested by a Retry-After header,
+// is interrupted as soon as the request context is done, in which case the
+// context error is returned.
 func (c *client) do(req *retryablehttp.Request, v interface{}) (*http.Response, error) {
 	resp, err := c.retryableHttpClient.Do(req)
+	err = c.describeRequestTimeout(req, err)
 	if err := handleUnsuccessfulResponse(req.URL.String(), resp, err); err != nil {
 		return resp, err
 	}
@@ -151,17 +304,55 @@ func (c *client) do(req *retryablehttp.Request, v interface{}) (*http.Response,
 // logRequestDump logs the request dump.
 func (c *client) logRequestDump(req *http.Request) {
 	if c.requestDumpLogger != nil {
-		dump, err := dumpRequestOut(req, c.dumpRequestBody)
+		// A streamed body is never dumped, since dumping
+		// it would read it whole into memory.
+		dump, err := dumpRequestOut(req, c.dumpRequestBody && !streamsBody(req))
 		if err == nil {
 			c.requestDumpLogger(dump)
 		}
 	}
 }
 
+// streamsBody reports whether the body of the request is streamed,
+// i.e. it has an unknown length and can be rebuilt through GetBody.
+func streamsBody(req *http.Request) bool {
+	return req.ContentLength < 0 && req.GetBody != nil
+}
+
+// retryableRequest wraps the request so that each attempt sends the
+// body from the beginning, even when a previous attempt consumed it
+// partially. The body is buffered, unless it is streamed, in which
+// case it is rebuilt for each attempt instead.
+func retryableRequest(req *http.Request) (*retryablehttp.Request, error) {
+	if !streamsBody(req) {
+		return retryablehttp.FromRequest(req)
+	}
+	streamed := *req
+	streamed.Body = nil
+	retryableReq, err := retryablehttp.FromRequest(&streamed)
+	if err != nil {
+		return nil, err
+	}
+	err = retryableReq.SetBody(retryablehttp.ReaderFunc(func() (io.Reader, error) {
+		return req.GetBody()
+	}))
+	return retryableReq, err
+}
+
 // sendRequest sends a request with or without payload.
 func (c *client) sendRequest(req *http.Request, v interface{}) (*http.Response, error) {
 	c.logRequestDump(req)
-	resp, err := c.do(&retryablehttp.Request{Request: req}, v)
+	retryableReq, err := retryableRequest(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading request body")
+	}
+	if c.retryReasonLog != nil {
+		// Counts the attempts of the request for the retry reason log.
+		retryableReq = retryableReq.WithContext(context.WithValue(req.Context(), retryAttemptsKey{}, new(int)))
+	}
+	retryableReq, cancel := c.withRequestTimeout(retryableReq)
+	resp, err := c.do(retryableReq, v)
+	cancelOnClose(resp, cancel)
 	if err != nil {
 		return resp, err
 	}
@@ -2,13 +2,21 @@ package httpclient
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -68,6 +76,947 @@ func TestEofRetryPolicy(t *testing.T) {
 	require.Equal(t, expectedError.Error(), err.Error())
 }
 
+func TestRetryAfterRespectsContextDeadline(t *testing.T) {
+	checkRetryPolicy := func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		return resp != nil && resp.StatusCode == http.StatusServiceUnavailable, err
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer svr.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	client := New(WithMaxRetries(1), WithCheckRetryPolicy(checkRetryPolicy))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, svr.URL, nil)
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	start := time.Now()
+	_, err = client.SendRequest(req)
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetryResendsFullBodyAfterPartialUpload(t *testing.T) {
+	checkRetryPolicy := func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		return err != nil, nil
+	}
+	body := bytes.Repeat([]byte("resume"), 1<<14)
+	attempts := 0
+	var received []byte
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			_, _ = io.ReadFull(r.Body, make([]byte, 1024))
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.Nil(t, err)
+			_ = conn.Close()
+			return
+		}
+		received, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"value"}`))
+	}))
+	defer svr.Close()
+	client := New(WithMaxRetries(1), WithCheckRetryPolicy(checkRetryPolicy))
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var output dummyType
+	_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Nil(t, err)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, body, received)
+	require.Equal(t, dummyType{Key: "value"}, output)
+}
+
+func TestDNSCache(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ttl             time.Duration
+		expectedLookups int
+	}{
+		{
+			name:            "resolved once within the TTL",
+			ttl:             time.Minute,
+			expectedLookups: 1,
+		},
+		{
+			name:            "resolved again after the TTL",
+			ttl:             time.Nanosecond,
+			expectedLookups: 3,
+		},
+	}
+	originalLookupHost := lookupHost
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				lookupHost = originalLookupHost
+			}()
+			lookups := 0
+			lookupHost = func(ctx context.Context, host string) ([]string, error) {
+				lookups++
+				require.Equal(t, "rps.test", host)
+				return []string{"127.0.0.1"}, nil
+			}
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer svr.Close()
+			url := strings.Replace(svr.URL, "127.0.0.1", "rps.test", 1)
+			client := New(WithDNSCache(tc.ttl))
+			for i := 0; i < 3; i++ {
+				req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, url, nil)
+				if err != nil {
+					t.Fatalf(`creating request for "%v": %v`, url, err)
+				}
+				// Forces a new connection, hence a new dial, per request.
+				req.Close = true
+				resp, err := client.SendRequest(req)
+				require.Nil(t, err)
+				resp.Body.Close()
+			}
+			require.Equal(t, tc.expectedLookups, lookups)
+		})
+	}
+}
+
+func TestDNSCacheInvalidationOnDialFailure(t *testing.T) {
+	originalLookupHost := lookupHost
+	defer func() {
+		lookupHost = originalLookupHost
+	}()
+	lookups := 0
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.0.0.1", "10.0.0.2"}, nil
+	}
+	var dialed []string
+	cache := newDNSCache(time.Minute, func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return nil, errors.New("connection refused")
+	})
+
+	_, err := cache.DialContext(context.TODO(), "tcp", "rps.test:443")
+	require.EqualError(t, err, "connection refused")
+	require.Equal(t, []string{"10.0.0.1:443", "10.0.0.2:443"}, dialed)
+	_, err = cache.DialContext(context.TODO(), "tcp", "rps.test:443")
+	require.NotNil(t, err)
+	require.Equal(t, 2, lookups)
+}
+
+func TestDualStackDial(t *testing.T) {
+	// Listening on all addresses accepts both IPv4 and IPv6 connections.
+	listener, err := net.Listen("tcp", ":0")
+	require.Nil(t, err)
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"value"}`))
+	}))
+	svr.Listener = listener
+	svr.Start()
+	defer svr.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+	client := New(WithDualStackDial(0))
+	for _, host := range []string{"127.0.0.1", "[::1]", "localhost"} {
+		url := fmt.Sprintf("http://%s:%d", host, port)
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf(`creating request for "%v": %v`, url, err)
+		}
+		var output dummyType
+		_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+		require.Nil(t, err, url)
+		require.Equal(t, dummyType{Key: "value"}, output)
+	}
+}
+
+func TestDualStackDialFallback(t *testing.T) {
+	testCases := []struct {
+		name            string
+		options         []Option
+		addrs           []string
+		expectedAddress string
+		expectedError   error
+	}{
+		{
+			name:          "addresses dialed in turn without dual stack dial",
+			options:       []Option{WithDNSCache(time.Minute)},
+			addrs:         []string{"2001:db8::1", "127.0.0.1"},
+			expectedError: context.DeadlineExceeded,
+		},
+		{
+			name:            "other family raced after the fallback delay",
+			options:         []Option{WithDualStackDial(10 * time.Millisecond)},
+			addrs:           []string{"2001:db8::1", "127.0.0.1"},
+			expectedAddress: "127.0.0.1:443",
+		},
+		{
+			name:            "cached addresses raced after the fallback delay",
+			options:         []Option{WithDualStackDial(10 * time.Millisecond), WithDNSCache(time.Minute)},
+			addrs:           []string{"2001:db8::1", "127.0.0.1"},
+			expectedAddress: "127.0.0.1:443",
+		},
+		{
+			name:            "other family dialed as soon as the preferred one fails",
+			options:         []Option{WithDualStackDial(time.Minute)},
+			addrs:           []string{"2001:db8::2", "127.0.0.1"},
+			expectedAddress: "127.0.0.1:443",
+		},
+		{
+			name:          "all families failing",
+			options:       []Option{WithDualStackDial(time.Minute)},
+			addrs:         []string{"2001:db8::2", "10.0.0.2"},
+			expectedError: errConnectionRefused,
+		},
+	}
+	originalLookupHost := lookupHost
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				lookupHost = originalLookupHost
+			}()
+			lookupHost = func(ctx context.Context, host string) ([]string, error) {
+				return tc.addrs, nil
+			}
+			// 2001:db8::1 never connects, while addresses
+			// ending in 2 are refused and others connect.
+			transport := &http.Transport{DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				switch {
+				case ctx.Err() != nil:
+					return nil, ctx.Err()
+				case strings.HasPrefix(address, "[2001:db8::1]"):
+					<-ctx.Done()
+					return nil, ctx.Err()
+				case strings.HasSuffix(address, "2]:443"), strings.HasSuffix(address, ".2:443"):
+					return nil, errConnectionRefused
+				}
+				conn, _ := net.Pipe()
+				return &addressedConn{Conn: conn, address: address}, nil
+			}}
+			newClient(tc.options).configureTransport(transport)
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+			conn, err := transport.DialContext(ctx, "tcp", "rps.test:443")
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.Nil(t, err)
+			defer conn.Close()
+			require.Equal(t, tc.expectedAddress, conn.(*addressedConn).address)
+		})
+	}
+}
+
+// errConnectionRefused is returned by the fake dials of the tests.
+var errConnectionRefused = errors.New("connection refused")
+
+// addressedConn is a connection remembering the address it was dialed to.
+type addressedConn struct {
+	net.Conn
+	address string
+}
+
+func TestHostHealthTracking(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tracking       bool
+		maxFailures    int
+		cooldown       time.Duration
+		pause          time.Duration
+		expectedErrors []bool
+		expectedHits   []int
+	}{
+		{
+			name:           "without tracking",
+			expectedErrors: []bool{true, true},
+			expectedHits:   []int{4, 0},
+		},
+		{
+			name:           "failing host skipped after a failure",
+			tracking:       true,
+			maxFailures:    1,
+			cooldown:       time.Minute,
+			expectedErrors: []bool{false, false},
+			expectedHits:   []int{1, 2},
+		},
+		{
+			name:           "failing host skipped after two failures in a row",
+			tracking:       true,
+			maxFailures:    2,
+			cooldown:       time.Minute,
+			expectedErrors: []bool{true, false},
+			expectedHits:   []int{2, 1},
+		},
+		{
+			name:           "failing host tried again after the cool-down",
+			tracking:       true,
+			maxFailures:    1,
+			cooldown:       50 * time.Millisecond,
+			pause:          100 * time.Millisecond,
+			expectedErrors: []bool{false, false},
+			expectedHits:   []int{2, 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits [2]atomic.Int32
+			newServer := func(i, statusCode int) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					hits[i].Add(1)
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(statusCode)
+					_, _ = w.Write([]byte(`{"key":"value"}`))
+				}))
+			}
+			failing, healthy := newServer(0, http.StatusServiceUnavailable), newServer(1, http.StatusOK)
+			defer failing.Close()
+			defer healthy.Close()
+			options := []Option{
+				WithMaxRetries(1),
+				WithRetryWaitMin(time.Millisecond),
+				WithRetryWaitMax(time.Millisecond),
+				WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+					return resp == nil || resp.StatusCode >= http.StatusInternalServerError, nil
+				}),
+			}
+			if tc.tracking {
+				options = append(options, WithHostHealthTracking(tc.maxFailures, tc.cooldown, failing.URL, healthy.URL))
+			}
+			client := New(options...)
+			var errs []bool
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, failing.URL, nil)
+				if err != nil {
+					t.Fatalf(`creating request for "%v": %v`, failing.URL, err)
+				}
+				var output dummyType
+				_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+				errs = append(errs, err != nil)
+				time.Sleep(tc.pause)
+			}
+			require.Equal(t, tc.expectedErrors, errs)
+			require.Equal(t, tc.expectedHits, []int{int(hits[0].Load()), int(hits[1].Load())})
+		})
+	}
+}
+
+func TestHostHealthObserve(t *testing.T) {
+	testCases := []struct {
+		name                string
+		maxFailures         int
+		cooldown            time.Duration
+		url                 string
+		failures            int
+		expectedMaxFailures int
+		expectedCooldown    time.Duration
+		expectedFailures    map[string]int
+		expectedDown        bool
+	}{
+		{
+			name:                "defaults",
+			url:                 "https://eu.example.com/api/parse",
+			failures:            2,
+			expectedMaxFailures: 3,
+			expectedCooldown:    30 * time.Second,
+			expectedFailures:    map[string]int{"eu.example.com": 2},
+		},
+		{
+			name:                "tracked host put in cool-down",
+			maxFailures:         2,
+			cooldown:            time.Minute,
+			url:                 "https://EU.example.com/api/parse",
+			failures:            2,
+			expectedMaxFailures: 2,
+			expectedCooldown:    time.Minute,
+			expectedFailures:    map[string]int{},
+			expectedDown:        true,
+		},
+		{
+			name:                "untracked host not counted",
+			maxFailures:         1,
+			cooldown:            time.Minute,
+			url:                 "https://us.example.com/api/parse",
+			failures:            2,
+			expectedMaxFailures: 1,
+			expectedCooldown:    time.Minute,
+			expectedFailures:    map[string]int{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHostHealth(tc.maxFailures, tc.cooldown, []string{"https://eu.example.com", "https://ap.example.com"})
+			require.Equal(t, tc.expectedMaxFailures, h.maxFailures)
+			require.Equal(t, tc.expectedCooldown, h.cooldown)
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, tc.url, nil)
+			if err != nil {
+				t.Fatalf(`creating request for "%v": %v`, tc.url, err)
+			}
+			for i := 0; i < tc.failures; i++ {
+				h.observe(req, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			}
+			require.Equal(t, tc.expectedFailures, h.failures)
+			require.Equal(t, tc.expectedDown, len(h.downUntil) > 0)
+		})
+	}
+}
+
+func TestIdleConnTimeout(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		options                 []Option
+		expectedIdleConnTimeout time.Duration
+	}{
+		{
+			name:                    "default transport timeout",
+			expectedIdleConnTimeout: 90 * time.Second,
+		},
+		{
+			name:                    "with idle conn timeout",
+			options:                 []Option{WithIdleConnTimeout(30 * time.Second)},
+			expectedIdleConnTimeout: 30 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := New(tc.options...).(*client)
+			require.True(t, ok)
+			wrapper, ok := c.retryableHttpClient.(*retryableHttpClientWrapper)
+			require.True(t, ok)
+			transport, ok := wrapper.rhc.HTTPClient.Transport.(*http.Transport)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedIdleConnTimeout, transport.IdleConnTimeout)
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	var attempts int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"value"}`))
+	}))
+	defer svr.Close()
+	c := New(
+		WithRequestTimeout(100*time.Millisecond),
+		WithMaxRetries(3),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return ctx.Err() == nil, err
+		}),
+	)
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, svr.URL, nil)
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	start := time.Now()
+	var output dummyType
+	_, err = c.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	require.ErrorIs(t, err, ErrRequestTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), "request timed out after 100ms")
+}
+
+func TestSendRequestWithStreamedBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		attempt := len(bodies)
+		mu.Unlock()
+		require.Equal(t, int64(-1), r.ContentLength)
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"value"}`))
+	}))
+	defer svr.Close()
+	var dump []byte
+	c := New(
+		WithMaxRetries(1),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return resp != nil && resp.StatusCode == http.StatusServiceUnavailable, err
+		}),
+		WithRequestDumpLogger(func(d []byte) { dump = d }, true),
+	)
+	getBody := func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"streamed":true}`)), nil
+	}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, nil)
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	req.Body, _ = getBody()
+	req.GetBody = getBody
+	req.ContentLength = -1
+	var output dummyType
+	_, err = c.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Nil(t, err)
+	require.Equal(t, "value", output.Key)
+	require.Equal(t, []string{`{"streamed":true}`, `{"streamed":true}`}, bodies)
+	require.NotContains(t, string(dump), "streamed")
+}
+
+func TestConnectionLimits(t *testing.T) {
+	testCases := []struct {
+		name                        string
+		options                     []Option
+		expectedMaxIdleConns        int
+		expectedMaxIdleConnsPerHost int
+		expectedMaxConnsPerHost     int
+	}{
+		{
+			name:                        "default transport limits",
+			expectedMaxIdleConns:        100,
+			expectedMaxIdleConnsPerHost: runtime.GOMAXPROCS(0) + 1,
+		},
+		{
+			name: "with connection limits",
+			options: []Option{
+				WithMaxIdleConns(10),
+				WithMaxIdleConnsPerHost(5),
+				WithMaxConnsPerHost(20),
+			},
+			expectedMaxIdleConns:        10,
+			expectedMaxIdleConnsPerHost: 5,
+			expectedMaxConnsPerHost:     20,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := New(tc.options...).(*client)
+			require.True(t, ok)
+			wrapper, ok := c.retryableHttpClient.(*retryableHttpClientWrapper)
+			require.True(t, ok)
+			transport, ok := wrapper.rhc.HTTPClient.Transport.(*http.Transport)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedMaxIdleConns, transport.MaxIdleConns)
+			require.Equal(t, tc.expectedMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+			require.Equal(t, tc.expectedMaxConnsPerHost, transport.MaxConnsPerHost)
+		})
+	}
+}
+
+func TestPerHostPools(t *testing.T) {
+	var mu sync.Mutex
+	newConns := make(map[string]int)
+	newServer := func() *httptest.Server {
+		svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"key":"value"}`))
+		}))
+		svr.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				mu.Lock()
+				newConns[conn.LocalAddr().String()]++
+				mu.Unlock()
+			}
+		}
+		svr.Start()
+		return svr
+	}
+	svr1, svr2 := newServer(), newServer()
+	defer svr1.Close()
+	defer svr2.Close()
+	c, ok := New(WithPerHostPools(), WithIdleConnTimeout(30*time.Second)).(*client)
+	require.True(t, ok)
+	for i := 0; i < 2; i++ {
+		for _, url := range []string{svr1.URL, svr2.URL} {
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, url, nil)
+			if err != nil {
+				t.Fatalf(`creating request for "%v": %v`, url, err)
+			}
+			var output dummyType
+			_, err = c.SendRequestAndUnmarshallJsonResponse(req, &output)
+			require.Nil(t, err)
+		}
+	}
+	wrapper, ok := c.retryableHttpClient.(*retryableHttpClientWrapper)
+	require.True(t, ok)
+	transport, ok := wrapper.rhc.HTTPClient.Transport.(*perHostTransport)
+	require.True(t, ok)
+	require.Len(t, transport.transports, 2)
+	host1, host2 := svr1.Listener.Addr().String(), svr2.Listener.Addr().String()
+	require.NotSame(t, transport.transports[host1], transport.transports[host2])
+	for _, host := range []string{host1, host2} {
+		require.Equal(t, 30*time.Second, transport.transports[host].IdleConnTimeout)
+		require.Equal(t, 1, newConns[host], host)
+	}
+}
+
+func TestMaxConnLifetime(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		expectedNewConns int
+	}{
+		{
+			name:             "connections are reused by default",
+			expectedNewConns: 1,
+		},
+		{
+			name:             "connections are recycled after the lifetime",
+			options:          []Option{WithMaxConnLifetime(50 * time.Millisecond)},
+			expectedNewConns: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			newConns := 0
+			svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				require.Equal(t, "resume", string(body))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"key":"value"}`))
+			}))
+			svr.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					mu.Lock()
+					newConns++
+					mu.Unlock()
+				}
+			}
+			svr.Start()
+			defer svr.Close()
+			client := New(tc.options...)
+			for i := 0; i < 3; i++ {
+				if i == 2 {
+					time.Sleep(100 * time.Millisecond)
+				}
+				req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, strings.NewReader("resume"))
+				if err != nil {
+					t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+				}
+				var output dummyType
+				_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+				require.Nil(t, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, tc.expectedNewConns, newConns)
+		})
+	}
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	_, _ = writer.Write([]byte(`{"key":"gzip"}`))
+	require.Nil(t, writer.Close())
+	testCases := []struct {
+		name                   string
+		options                []Option
+		contentEncoding        string
+		body                   []byte
+		expectedAcceptEncoding string
+		expectedOutput         string
+		expectedError          error
+	}{
+		{
+			name:                   "gzip response",
+			options:                []Option{WithCompressionNegotiation()},
+			contentEncoding:        "gzip",
+			body:                   gzipped.Bytes(),
+			expectedAcceptEncoding: acceptEncoding(),
+			expectedOutput:         "gzip",
+		},
+		{
+			name:                   "uncompressed response",
+			options:                []Option{WithCompressionNegotiation()},
+			body:                   []byte(`{"key":"identity"}`),
+			expectedAcceptEncoding: acceptEncoding(),
+			expectedOutput:         "identity",
+		},
+		{
+			name:                   "unsupported content encoding",
+			options:                []Option{WithCompressionNegotiation()},
+			contentEncoding:        "compress",
+			body:                   []byte(`{"key":"compress"}`),
+			expectedAcceptEncoding: acceptEncoding(),
+			expectedError:          ErrUnsupportedContentEncoding,
+		},
+		{
+			name:                   "transparent gzip by default",
+			contentEncoding:        "gzip",
+			body:                   gzipped.Bytes(),
+			expectedAcceptEncoding: "gzip",
+			expectedOutput:         "gzip",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acceptEncodings := make(chan string, 1)
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncodings <- r.Header.Get("Accept-Encoding")
+				w.Header().Set("Content-Type", "application/json")
+				if tc.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tc.contentEncoding)
+				}
+				_, _ = w.Write(tc.body)
+			}))
+			defer svr.Close()
+			client := New(tc.options...)
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, svr.URL, nil)
+			if err != nil {
+				t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+			}
+			var output dummyType
+			_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+			require.Equal(t, tc.expectedAcceptEncoding, <-acceptEncodings)
+			if tc.expectedError != nil {
+				require.True(t, errors.Is(err, tc.expectedError))
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, output.Key)
+		})
+	}
+}
+
+func TestRetryReasonLog(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		attempt := attempts
+		mu.Unlock()
+		switch attempt {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		case 3:
+			// Resets the connection instead of responding.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			_ = conn.(*net.TCPConn).SetLinger(0)
+			_ = conn.Close()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"key":"value"}`))
+		}
+	}))
+	defer svr.Close()
+	var reasons []string
+	client := New(
+		WithMaxRetries(3),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return resp == nil || resp.StatusCode >= http.StatusInternalServerError, nil
+		}),
+		WithRetryReasonLog(func(attempt int, reason string) {
+			reasons = append(reasons, fmt.Sprintf("%d: %s", attempt, reason))
+		}),
+	)
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, strings.NewReader("resume"))
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var output dummyType
+	_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Nil(t, err)
+	require.Equal(t, "value", output.Key)
+	require.Equal(t, []string{
+		"1: status 503, Retry-After honored",
+		"2: status 502",
+		"3: connection reset",
+	}, reasons)
+}
+
+func TestRetryReasonLogWhenRetriesAreExhausted(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer svr.Close()
+	var reasons []string
+	client := New(
+		WithMaxRetries(1),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return resp == nil || resp.StatusCode >= http.StatusInternalServerError, nil
+		}),
+		WithRetryReasonLog(func(attempt int, reason string) {
+			reasons = append(reasons, fmt.Sprintf("%d: %s", attempt, reason))
+		}),
+	)
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, strings.NewReader("resume"))
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var output dummyType
+	_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.NotNil(t, err)
+	// The second attempt is the last one, hence not reported as retried.
+	require.Equal(t, []string{"1: status 503"}, reasons)
+}
+
+func TestRequestHook(t *testing.T) {
+	var mu sync.Mutex
+	var headers []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get("X-Attempt"))
+		attempt := len(headers)
+		mu.Unlock()
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"value"}`))
+	}))
+	defer svr.Close()
+	client := New(
+		WithMaxRetries(1),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return resp == nil || resp.StatusCode >= http.StatusInternalServerError, nil
+		}),
+		WithRequestHook(func(req *http.Request, attempt int) {
+			req.Header.Set("X-Attempt", strconv.Itoa(attempt))
+		}),
+	)
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, strings.NewReader("resume"))
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var output dummyType
+	_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+	require.Nil(t, err)
+	require.Equal(t, "value", output.Key)
+	require.Equal(t, []string{"0", "1"}, headers)
+}
+
+func TestRetryReason(t *testing.T) {
+	testCases := []struct {
+		name           string
+		resp           *http.Response
+		err            error
+		expectedReason string
+	}{
+		{
+			name:           "status",
+			resp:           &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}},
+			expectedReason: "status 500",
+		},
+		{
+			name:           "retry after",
+			resp:           &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1"}}},
+			expectedReason: "status 429, Retry-After honored",
+		},
+		{
+			name:           "retry after ignored by the backoff",
+			resp:           &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{"Retry-After": {"1"}}},
+			expectedReason: "status 502",
+		},
+		{
+			name:           "connection refused",
+			err:            &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED},
+			expectedReason: "connection refused",
+		},
+		{
+			name:           "connection closed",
+			err:            fmt.Errorf("reading response: %w", io.EOF),
+			expectedReason: "connection closed",
+		},
+		{
+			name:           "attempt timed out",
+			err:            &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded},
+			expectedReason: "attempt timed out",
+		},
+		{
+			name:           "other error",
+			err:            errors.New("tls: handshake failure"),
+			expectedReason: "tls: handshake failure",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedReason, retryReason(tc.resp, tc.err))
+		})
+	}
+}
+
+func TestBodyReadIdleTimeout(t *testing.T) {
+	testCases := []struct {
+		name          string
+		chunks        []string
+		interval      time.Duration
+		stall         bool
+		expectedError error
+	}{
+		{
+			name:     "slow but steady body",
+			chunks:   []string{`{"key"`, `:`, `"val`, `ue"}`},
+			interval: 20 * time.Millisecond,
+		},
+		{
+			name:          "body stalling mid-way",
+			chunks:        []string{`{"key"`, `:`},
+			stall:         true,
+			expectedError: ErrBodyReadIdleTimeout,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The closing of the connection is only noticed
+				// once the request body was read.
+				_, _ = io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/json")
+				for _, chunk := range tc.chunks {
+					_, _ = w.Write([]byte(chunk))
+					w.(http.Flusher).Flush()
+					time.Sleep(tc.interval)
+				}
+				if tc.stall {
+					<-r.Context().Done()
+				}
+			}))
+			defer svr.Close()
+			client := New(WithBodyReadIdleTimeout(50 * time.Millisecond))
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, svr.URL, strings.NewReader("resume"))
+			if err != nil {
+				t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+			}
+			var output dummyType
+			_, err = client.SendRequestAndUnmarshallJsonResponse(req, &output)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				var netErr net.Error
+				require.ErrorAs(t, err, &netErr)
+				require.True(t, netErr.Timeout())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "value", output.Key)
+		})
+	}
+}
+
 func TestNew(t *testing.T) {
 	testCases := []struct {
 		name                        string
@@ -300,6 +1249,36 @@ func TestSendRequestAndUnmarshallJsonResponse(t *testing.T) {
 				Key: "value",
 			},
 		},
+		{
+			name: "json content type",
+			mockClosure: func(r *retryableHttpClientMock) {
+				body := `{"key":"value"}`
+				resp := &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+				}
+				r.Resp = resp
+			},
+			expectedData: dummyType{
+				Key: "value",
+			},
+		},
+		{
+			name: "unexpected content type",
+			mockClosure: func(r *retryableHttpClientMock) {
+				body := `<html><body>Bad Gateway</body></html>`
+				resp := &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+				}
+				r.Resp = resp
+			},
+			expectedError: errors.New(`request to http://localhost/some/path failed. ` +
+				`httpStatus: [ 200 ] responseBody: [ <html><body>Bad Gateway</body></html> ] ` +
+				`error: [ "text/html": unexpected content type ]`),
+		},
 		{
 			name: "error when decoding response",
 			mockClosure: func(r *retryableHttpClientMock) {
@@ -407,6 +1386,25 @@ func TestSendRequestAndUnmarshallJsonResponse(t *testing.T) {
 	}
 }
 
+func TestUnexpectedContentType(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(strings.Repeat("a", 2*bodySnippetLength)))
+	}))
+	defer svr.Close()
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, svr.URL, nil)
+	if err != nil {
+		t.Fatalf(`creating request for "%v": %v`, svr.URL, err)
+	}
+	var data dummyType
+	_, err = New().SendRequestAndUnmarshallJsonResponse(req, &data)
+	require.True(t, errors.Is(err, ErrUnexpectedContentType))
+	var httpErr *HttpError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusOK, httpErr.StatusCode)
+	require.Len(t, httpErr.Body, bodySnippetLength)
+}
+
 type retryableHttpClientMock struct {
 	retryableHttpClient
 	Resp *http.Response
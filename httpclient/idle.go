@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// idleTimeoutError is returned when a response body stalls
+// for longer than the body read idle timeout.
+type idleTimeoutError struct{}
+
+func (idleTimeoutError) Error() string   { return "body read idle timeout" }
+func (idleTimeoutError) Timeout() bool   { return true }
+func (idleTimeoutError) Temporary() bool { return true }
+
+// ErrBodyReadIdleTimeout is returned when no byte of the response body
+// was received for longer than the timeout set through
+// WithBodyReadIdleTimeout. It is a net.Error whose Timeout method
+// returns true.
+var ErrBodyReadIdleTimeout error = idleTimeoutError{}
+
+// idleTimeoutBody aborts the request once no byte was read
+// from the body for longer than the timeout.
+type idleTimeoutBody struct {
+	body     io.ReadCloser
+	timeout  time.Duration
+	timer    *time.Timer
+	cancel   context.CancelFunc
+	timedOut atomic.Bool
+}
+
+// newIdleTimeoutBody wraps the body so that the request is
+// canceled through cancel once the body stalls.
+func newIdleTimeoutBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) *idleTimeoutBody {
+	b := &idleTimeoutBody{body: body, timeout: timeout, cancel: cancel}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.timedOut.Store(true)
+		cancel()
+	})
+	return b
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if b.timedOut.Load() {
+		return n, ErrBodyReadIdleTimeout
+	}
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	b.cancel()
+	return b.body.Close()
+}
+
+// idleTimeoutTransport aborts the requests whose
+// response body stalls for longer than the timeout.
+type idleTimeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip executes a single HTTP transaction. It implements the http.RoundTripper interface.
+func (t *idleTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = newIdleTimeoutBody(resp.Body, t.timeout, cancel)
+	return resp, nil
+}
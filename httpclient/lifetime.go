@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// errConnExpired is returned when writing a new request to a
+// connection older than its maximum lifetime. As nothing was written,
+// the transport retries the request on a new connection.
+var errConnExpired = errors.New("connection exceeded its maximum lifetime")
+
+// lifetimeConn is a connection refusing new requests once
+// it is older than its maximum lifetime.
+type lifetimeConn struct {
+	net.Conn
+	expires time.Time
+
+	mu      sync.Mutex
+	writing bool
+}
+
+// Read marks the end of the request being written, if any.
+func (c *lifetimeConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	c.writing = false
+	c.mu.Unlock()
+	return c.Conn.Read(b)
+}
+
+// Write closes the connection when a new request is written after
+// the connection expired. Requests being written are not interrupted.
+func (c *lifetimeConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	starting := !c.writing
+	c.writing = true
+	c.mu.Unlock()
+	if starting && time.Now().After(c.expires) {
+		c.Conn.Close()
+		return 0, errConnExpired
+	}
+	return c.Conn.Write(b)
+}
+
+// maxConnLifetimeDial returns a dial function whose connections
+// are recycled once they are older than lifetime.
+func maxConnLifetimeDial(lifetime time.Duration, dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return &lifetimeConn{Conn: conn, expires: time.Now().Add(lifetime)}, nil
+	}
+}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -71,3 +72,137 @@ func WithRequestDumpLogger(requestDumpLogger func(dump []byte), dumpRequestBody
 		c.dumpRequestBody = dumpRequestBody
 	}
 }
+
+// WithDNSCache resolves each host at most once per TTL,
+// saving repeated DNS lookups under high request rates.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *client) {
+		c.dnsCacheTTL = ttl
+	}
+}
+
+// WithDualStackDial connects to hosts reachable over both IPv4 and
+// IPv6 by racing both families (Happy Eyeballs), minimizing connection
+// latency on networks with broken IPv6: when the family of the first
+// resolved address has not connected within fallbackDelay (300ms if
+// not positive), the other family is dialed concurrently, and the first
+// connection is used. Along with WithDNSCache, the cached addresses
+// are raced.
+func WithDualStackDial(fallbackDelay time.Duration) Option {
+	return func(c *client) {
+		c.dualStackDial = true
+		c.dualStackFallback = fallbackDelay
+	}
+}
+
+// WithMaxConnLifetime recycles connections older than d, closing them
+// before their next request, so that load is redistributed across the
+// backends behind a load balancer after scaling events.
+func WithMaxConnLifetime(d time.Duration) Option {
+	return func(c *client) {
+		c.maxConnLifetime = d
+	}
+}
+
+// WithPerAttemptTimeout limits the time of each attempt, separately
+// from the overall timeout set through the context of the request.
+// Attempts exceeding it are abandoned and retried, up to the maximum
+// number of retries, improving tail latency when one attempt hangs.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(c *client) {
+		c.perAttemptTimeout = d
+	}
+}
+
+// WithRequestTimeout limits the overall time of a request, retries and
+// the waits between them included, so that a stalled service does not
+// hang the request. The response body must be read before it elapses.
+// Requests exceeding it fail with an error wrapping ErrRequestTimeout.
+// It bounds the context of the request rather than setting the Timeout
+// of the underlying http.Client, which applies to each attempt on its
+// own and is set through WithPerAttemptTimeout.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithTimeoutRetriesByPolicy leaves the retries of the attempts exceeding
+// the per-attempt timeout to the retry policy, instead of always retrying
+// them, e.g. when the policy bounds them on its own.
+func WithTimeoutRetriesByPolicy() Option {
+	return func(c *client) {
+		c.timeoutsByPolicy = true
+	}
+}
+
+// WithCompressionNegotiation advertises the supported content encodings
+// through the Accept-Encoding header and decompresses the responses
+// accordingly, falling back to identity when they are not compressed.
+// Gzip is always supported, and Brotli when built with the "brotli" tag.
+// Responses with other encodings fail with ErrUnsupportedContentEncoding.
+func WithCompressionNegotiation() Option {
+	return func(c *client) {
+		c.compression = true
+	}
+}
+
+// WithRetryReasonLog specifies a function that is called before each
+// retry with the number of the failed attempt, starting from 1, and a
+// human-readable reason, e.g. "status 503" or "connection reset".
+func WithRetryReasonLog(log func(attempt int, reason string)) Option {
+	return func(c *client) {
+		c.retryReasonLog = log
+	}
+}
+
+// WithBodyReadIdleTimeout aborts the requests whose response body
+// stalls, i.e. no byte is received for longer than d, returning
+// ErrBodyReadIdleTimeout. Unlike the overall timeout, slow but steady
+// bodies, such as large raw-text responses, are not aborted.
+func WithBodyReadIdleTimeout(d time.Duration) Option {
+	return func(c *client) {
+		c.bodyReadIdleTimeout = d
+	}
+}
+
+// WithIdleConnTimeout closes the pooled connections that stay idle for
+// longer than d, e.g. to match the keep-alive timeout of the service,
+// avoiding "connection reset" errors when reusing stale connections.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *client) {
+		c.idleConnTimeout = d
+	}
+}
+
+// WithPerHostPools keeps a separate connection pool per host, each
+// honoring the configured limits, e.g. so that failing over between
+// regional endpoints does not thrash a single shared pool.
+func WithPerHostPools() Option {
+	return func(c *client) {
+		c.perHostPools = true
+	}
+}
+
+// WithHostHealthTracking tracks the failures, errors or 5xx responses,
+// of the hosts of the given base URLs, interchangeable endpoints of the
+// same service in order of preference, e.g. regional ones. A host failing
+// maxFailures times in a row, 3 if not positive, is skipped for the
+// cool-down, 30 seconds if not positive, its requests, retries included,
+// being sent to the first healthy host instead, with the same path. The
+// requests to other hosts are neither counted nor routed. It implies
+// WithPerHostPools.
+func WithHostHealthTracking(maxFailures int, cooldown time.Duration, baseUrls ...string) Option {
+	return func(c *client) {
+		c.hostHealth = newHostHealth(maxFailures, cooldown, baseUrls)
+	}
+}
+
+// WithRequestHook specifies a function that is called with the request
+// before each attempt, starting from 0, e.g. to set the headers depending
+// on the time of the attempt.
+func WithRequestHook(hook func(req *http.Request, attempt int)) Option {
+	return func(c *client) {
+		c.requestHook = hook
+	}
+}
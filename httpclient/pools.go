@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// perHostTransport routes each request to a transport of its own per
+// host, cloned from the base one, so that every host has a separate
+// connection pool honoring the configured limits, and failing over
+// from one host to another does not thrash a shared pool. With a host
+// health tracker, the requests to the hosts cooling down are routed
+// to a healthy host instead.
+type perHostTransport struct {
+	base       *http.Transport
+	health     *hostHealth
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+// newPerHostTransport returns a perHostTransport cloning the base
+// transport, or the base transport itself if it cannot be cloned.
+// The health tracker, if not nil, is fed with the outcome of each request.
+func newPerHostTransport(base http.RoundTripper, health *hostHealth) http.RoundTripper {
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+	return &perHostTransport{base: transport, health: health, transports: make(map[string]*http.Transport)}
+}
+
+// transportFor returns the transport of the given host,
+// cloning the base transport on first use.
+func (t *perHostTransport) transportFor(host string) *http.Transport {
+	host = strings.ToLower(host)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	transport, ok := t.transports[host]
+	if !ok {
+		transport = t.base.Clone()
+		t.transports[host] = transport
+	}
+	return transport
+}
+
+// RoundTrip sends the request through the transport of its host,
+// or of the healthy host it is routed to.
+func (t *perHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.health == nil {
+		return t.transportFor(req.URL.Host).RoundTrip(req)
+	}
+	req = t.health.route(req)
+	resp, err := t.transportFor(req.URL.Host).RoundTrip(req)
+	t.health.observe(req, resp, err)
+	return resp, err
+}
+
+// CloseIdleConnections closes the idle connections of every host.
+func (t *perHostTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, transport := range t.transports {
+		transport.CloseIdleConnections()
+	}
+}
@@ -22,6 +22,21 @@ type retryableHttpClient interface {
 	// SetCheckRetry specifies a custom retry policy function.
 	SetCheckRetry(checkRetry retryablehttp.CheckRetry)
 
+	// SetAttemptTimeout sets the time limit of each attempt.
+	SetAttemptTimeout(timeout time.Duration)
+
+	// ConfigureTransport applies the given configuration
+	// to the underlying transport.
+	ConfigureTransport(configure func(transport *http.Transport))
+
+	// WrapTransport replaces the underlying transport
+	// with the one returned by wrap.
+	WrapTransport(wrap func(transport http.RoundTripper) http.RoundTripper)
+
+	// SetRequestHook specifies the function called
+	// with the request before each attempt.
+	SetRequestHook(hook func(req *http.Request, attempt int))
+
 	// Do sends an HTTP request and returns an HTTP response, applying retry logic as configured.
 	Do(req *retryablehttp.Request) (*http.Response, error)
 }
@@ -53,6 +68,26 @@ func (r *retryableHttpClientWrapper) SetCheckRetry(checkRetry retryablehttp.Chec
 	r.rhc.CheckRetry = checkRetry
 }
 
+func (r *retryableHttpClientWrapper) SetAttemptTimeout(timeout time.Duration) {
+	r.rhc.HTTPClient.Timeout = timeout
+}
+
+func (r *retryableHttpClientWrapper) ConfigureTransport(configure func(transport *http.Transport)) {
+	if transport, ok := r.rhc.HTTPClient.Transport.(*http.Transport); ok {
+		configure(transport)
+	}
+}
+
+func (r *retryableHttpClientWrapper) WrapTransport(wrap func(transport http.RoundTripper) http.RoundTripper) {
+	r.rhc.HTTPClient.Transport = wrap(r.rhc.HTTPClient.Transport)
+}
+
+func (r *retryableHttpClientWrapper) SetRequestHook(hook func(req *http.Request, attempt int)) {
+	r.rhc.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		hook(req, attempt)
+	}
+}
+
 func (r *retryableHttpClientWrapper) Do(req *retryablehttp.Request) (*http.Response, error) {
 	return r.rhc.Do(req)
 }
@@ -0,0 +1,63 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+)
+
+// retryAttemptsKey is the context key of the number
+// of attempts of a request whose retries are logged.
+type retryAttemptsKey struct{}
+
+// retryReason describes why an attempt is retried.
+func retryReason(resp *http.Response, err error) string {
+	var netErr net.Error
+	switch {
+	case resp != nil:
+		reason := fmt.Sprintf("status %d", resp.StatusCode)
+		// Honored by the default backoff of retryablehttp.
+		if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) &&
+			resp.Header.Get("Retry-After") != "" {
+			reason += ", Retry-After honored"
+		}
+		return reason
+	case errors.Is(err, syscall.ECONNRESET):
+		return "connection reset"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return "connection closed"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "attempt timed out"
+	case err != nil:
+		return err.Error()
+	default:
+		return "unknown"
+	}
+}
+
+// logRetryReasons wraps the retry policy so that the reason of each
+// retry is logged along with the number of the failed attempt,
+// starting from 1. The decision is left to the policy, and nothing is
+// logged after the last of the 1+maxRetries attempts, which is never
+// retried whatever the policy says.
+func logRetryReasons(checkRetryPolicy retryablehttp.CheckRetry, maxRetries int, log func(attempt int, reason string)) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		attempts, _ := ctx.Value(retryAttemptsKey{}).(*int)
+		if attempts != nil {
+			*attempts++
+		}
+		retry, checkErr := checkRetryPolicy(ctx, resp, err)
+		if retry && attempts != nil && *attempts <= maxRetries {
+			log(*attempts, retryReason(resp, err))
+		}
+		return retry, checkErr
+	}
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+)
+
+// ErrRequestTimeout is returned along with the underlying error when the
+// request, retries included, took longer than the timeout set through
+// WithRequestTimeout.
+var ErrRequestTimeout = errors.New("request timed out")
+
+// requestTimeoutError is returned when the request timed out, along with
+// the timeout, so that the message tells how long the request took.
+type requestTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *requestTimeoutError) Error() string {
+	return fmt.Sprintf("%v after %s: %v", ErrRequestTimeout, e.timeout, e.err)
+}
+
+// Unwrap returns both ErrRequestTimeout and the underlying error.
+func (e *requestTimeoutError) Unwrap() []error {
+	return []error{ErrRequestTimeout, e.err}
+}
+
+// withRequestTimeout bounds the request, retries included, by the
+// request timeout. The returned function releases the context and
+// must be called once the response, if any, is handled.
+func (c *client) withRequestTimeout(req *retryablehttp.Request) (*retryablehttp.Request, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return req, func() {}
+	}
+	// The cause is the error of the attempts aborted by the timeout.
+	cause := &requestTimeoutError{timeout: c.requestTimeout, err: context.DeadlineExceeded}
+	ctx, cancel := context.WithTimeoutCause(req.Context(), c.requestTimeout, cause)
+	return req.WithContext(ctx), cancel
+}
+
+// describeRequestTimeout tells that the request timed out, along with the
+// timeout, when it timed out between attempts rather than during one.
+func (c *client) describeRequestTimeout(req *retryablehttp.Request, err error) error {
+	cause, ok := context.Cause(req.Context()).(*requestTimeoutError)
+	if err == nil || !ok || errors.Is(err, ErrRequestTimeout) {
+		return err
+	}
+	return &requestTimeoutError{timeout: cause.timeout, err: err}
+}
+
+// cancelingBody cancels the context of the request once closed.
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelingBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// cancelOnClose cancels the context of the request once the response
+// body is closed, since the body is read after the request is sent.
+func cancelOnClose(resp *http.Response, cancel context.CancelFunc) {
+	if resp == nil || resp.Body == nil {
+		cancel()
+		return
+	}
+	resp.Body = &cancelingBody{ReadCloser: resp.Body, cancel: cancel}
+}
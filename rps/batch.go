@@ -0,0 +1,171 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// batchConcurrency is the maximum number of documents
+// of a batch that are parsed concurrently.
+const batchConcurrency = 8
+
+// parallelNormalizationThreshold is the size of a batch above which the
+// parsed resumes are normalized on a pool of their own, if enabled
+// through WithNormalizationConcurrency.
+const parallelNormalizationThreshold = 4 * batchConcurrency
+
+// MultiError is returned by ParseDocumentsAgg when some of the documents
+// could not be parsed. Errors holds the error of each document by index,
+// nil for the successful ones. errors.Is and errors.As look through the
+// errors of every document.
+type MultiError struct {
+	Errors []error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *MultiError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for i, err := range e.Errors {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("[%d]: %v", i, err))
+		}
+	}
+	return fmt.Sprintf("failed to parse %d of %d documents: %s",
+		len(messages), len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed documents.
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// aggregate returns a *MultiError holding the
+// errors, or nil if every document succeeded.
+func aggregate(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return &MultiError{Errors: errs}
+		}
+	}
+	return nil
+}
+
+// batchProgress reports the progress of a batch to a callback,
+// one call at a time so that done increases monotonically.
+type batchProgress struct {
+	mu       sync.Mutex
+	done     int
+	total    int
+	callback func(done, total int)
+}
+
+// complete counts a completed document and reports the progress.
+func (p *batchProgress) complete() {
+	if p.callback == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.callback(p.done, p.total)
+}
+
+// runConcurrently calls f with each index from 0 to n-1,
+// running at most concurrency calls at a time.
+func runConcurrently(n, concurrency int, f func(i int)) {
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			f(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// parseDocuments parses the documents concurrently through parse and
+// returns the resumes and errors in the same order as the documents.
+// The progress callback, if any, is called as each document completes.
+func parseDocuments(ctx context.Context, docs [][]byte, progressCallback func(done, total int), parse func(ctx context.Context, doc []byte) (*Resume, error)) ([]*Resume, []error) {
+	resumes := make([]*Resume, len(docs))
+	errs := make([]error, len(docs))
+	progress := &batchProgress{total: len(docs), callback: progressCallback}
+	runConcurrently(len(docs), batchConcurrency, func(i int) {
+		resumes[i], errs[i] = parse(ctx, docs[i])
+		progress.complete()
+	})
+	return resumes, errs
+}
+
+// ParseDocuments sends each document for parsing in its own request,
+// a few at a time, and returns the resumes and errors in the same order
+// as the documents. The call options apply to every document, hence
+// WithCallMetadata must not be used, as the calls run concurrently.
+func (r *resumeParsingServiceClient) ParseDocuments(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, []error) {
+	if r.normalizeWorkers > 0 && len(docs) > parallelNormalizationThreshold {
+		return r.parseDocumentsWithParallelNormalization(ctx, docs, options)
+	}
+	return parseDocuments(ctx, docs, r.batchProgress, func(ctx context.Context, doc []byte) (*Resume, error) {
+		return r.ParseDocument(ctx, doc, options...)
+	})
+}
+
+// parseDocumentsWithParallelNormalization parses the documents,
+// deferring the post-processing of the resumes (normalization and
+// response hook) to the pool of workers set through
+// WithNormalizationConcurrency, so that large batches are not bound by
+// the concurrency of the requests. Each resume is post-processed as soon
+// as it is fetched, by a single worker, hence the results are the same
+// as those of the sequential path. A document is only reported complete
+// to the progress callback once post-processed.
+func (r *resumeParsingServiceClient) parseDocumentsWithParallelNormalization(ctx context.Context, docs [][]byte, options []CallOption) ([]*Resume, []error) {
+	resumes := make([]*Resume, len(docs))
+	errs := make([]error, len(docs))
+	postProcesses := make([]func() error, len(docs))
+	progress := &batchProgress{total: len(docs), callback: r.batchProgress}
+	fetched := make(chan int, len(docs))
+	var wg sync.WaitGroup
+	for w := 0; w < r.normalizeWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range fetched {
+				if errs[i] == nil && postProcesses[i] != nil {
+					if err := postProcesses[i](); err != nil {
+						resumes[i], errs[i] = nil, err
+					}
+				}
+				progress.complete()
+			}
+		}()
+	}
+	runConcurrently(len(docs), batchConcurrency, func(i int) {
+		deferred := withDeferredPostProcessing(func(postProcess func() error) {
+			postProcesses[i] = postProcess
+		})
+		resumes[i], errs[i] = r.ParseDocument(ctx, docs[i], append(options[:len(options):len(options)], deferred)...)
+		fetched <- i
+	})
+	close(fetched)
+	wg.Wait()
+	return resumes, errs
+}
+
+// ParseDocumentsAgg is like ParseDocuments, but aggregates the errors
+// into a *MultiError, returned only if some of the documents failed.
+func (r *resumeParsingServiceClient) ParseDocumentsAgg(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error) {
+	resumes, errs := r.ParseDocuments(ctx, docs, options...)
+	return resumes, aggregate(errs)
+}
@@ -0,0 +1,332 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TalentInc/resume-parsing-service-client/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchServer returns a server responding with the document
+// as first name, or with a 504 if the document is "timeout".
+func newBatchServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body parseDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		doc, err := base64.StdEncoding.DecodeString(body.Base64Data)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if string(doc) == "timeout" {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Resume{FirstName: string(doc)})
+	}))
+}
+
+func TestParseDocuments(t *testing.T) {
+	svr := newBatchServer()
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+	docs := make([][]byte, 0, 2*batchConcurrency)
+	for i := 0; i < 2*batchConcurrency; i++ {
+		docs = append(docs, []byte{'a' + byte(i)})
+	}
+	docs[3] = []byte("timeout")
+	resumes, errs := client.ParseDocuments(context.TODO(), docs)
+	require.Len(t, resumes, len(docs))
+	require.Len(t, errs, len(docs))
+	for i, doc := range docs {
+		if i == 3 {
+			require.Nil(t, resumes[i])
+			require.ErrorIs(t, errs[i], ErrGatewayTimeout)
+			continue
+		}
+		require.Nil(t, errs[i])
+		require.Equal(t, string(doc), resumes[i].FirstName)
+	}
+}
+
+func TestParseDocumentsAgg(t *testing.T) {
+	testCases := []struct {
+		name           string
+		docs           []string
+		expectedNames  []string
+		expectedFailed []int
+	}{
+		{
+			name:          "every document succeeds",
+			docs:          []string{"Morgana", "John"},
+			expectedNames: []string{"Morgana", "John"},
+		},
+		{
+			name:           "some documents fail",
+			docs:           []string{"timeout", "Morgana", "timeout"},
+			expectedNames:  []string{"", "Morgana", ""},
+			expectedFailed: []int{0, 2},
+		},
+		{
+			name: "no documents",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := newBatchServer()
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			docs := make([][]byte, 0, len(tc.docs))
+			for _, doc := range tc.docs {
+				docs = append(docs, []byte(doc))
+			}
+			resumes, err := client.ParseDocumentsAgg(context.TODO(), docs)
+			require.Len(t, resumes, len(tc.docs))
+			for i, name := range tc.expectedNames {
+				if name == "" {
+					require.Nil(t, resumes[i])
+					continue
+				}
+				require.Equal(t, name, resumes[i].FirstName)
+			}
+			if tc.expectedFailed == nil {
+				require.Nil(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrGatewayTimeout)
+			require.True(t, IsTimeout(err))
+			var multiErr *MultiError
+			require.True(t, errors.As(err, &multiErr))
+			require.Len(t, multiErr.Errors, len(tc.docs))
+			require.Len(t, multiErr.Unwrap(), len(tc.expectedFailed))
+			for _, i := range tc.expectedFailed {
+				require.ErrorIs(t, multiErr.Errors[i], ErrGatewayTimeout)
+			}
+		})
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	err := &MultiError{Errors: []error{nil, errors.New("unsupported document"), ErrBodyTooLarge}}
+	require.Equal(t, "failed to parse 2 of 3 documents: [1]: unsupported document; [2]: "+ErrBodyTooLarge.Error(), err.Error())
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	require.NotErrorIs(t, err, ErrChecksumMismatch)
+	require.Nil(t, aggregate([]error{nil, nil}))
+}
+
+func TestParseDocumentsWithBatchProgress(t *testing.T) {
+	svr := newBatchServer()
+	defer svr.Close()
+	var done, totals []int
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithBatchProgress(func(d, total int) {
+			done = append(done, d)
+			totals = append(totals, total)
+		}),
+	)
+	docs := make([][]byte, 0, 3*batchConcurrency)
+	expectedDone := make([]int, 0, 3*batchConcurrency)
+	expectedTotals := make([]int, 0, 3*batchConcurrency)
+	for i := 0; i < 3*batchConcurrency; i++ {
+		// Failed documents count as completed.
+		doc := []byte{'a' + byte(i)}
+		if i%5 == 0 {
+			doc = []byte("timeout")
+		}
+		docs = append(docs, doc)
+		expectedDone = append(expectedDone, i+1)
+		expectedTotals = append(expectedTotals, 3*batchConcurrency)
+	}
+	_, _ = client.ParseDocuments(context.TODO(), docs)
+	require.Equal(t, expectedDone, done)
+	require.Equal(t, expectedTotals, totals)
+}
+
+func TestParseDocumentsWithNormalizationConcurrency(t *testing.T) {
+	svr := newBatchServer()
+	defer svr.Close()
+	docs := make([][]byte, 0, 2*parallelNormalizationThreshold)
+	for i := 0; i < 2*parallelNormalizationThreshold; i++ {
+		docs = append(docs, []byte(fmt.Sprintf(" document %d ", i)))
+	}
+	docs[3] = []byte("timeout")
+	docs[5] = []byte("invalid")
+	newClient := func(hookCalls *atomic.Int32, options ...Option) ResumeParsingServiceClient {
+		return NewResumeParsingServiceClient("TOKEN", svr.URL, append(options,
+			WithNormalizeWhitespace(),
+			WithNormalizers(func(resume *Resume) error {
+				if resume.FirstName == "invalid" {
+					return errors.New("invalid first name")
+				}
+				resume.FirstName = strings.ToUpper(resume.FirstName)
+				return nil
+			}),
+			WithResponseHook(func(resume *Resume, raw []byte) {
+				hookCalls.Add(1)
+			}),
+		)...)
+	}
+	var sequentialHookCalls, parallelHookCalls atomic.Int32
+	sequentialResumes, sequentialErrs := newClient(&sequentialHookCalls).ParseDocuments(context.TODO(), docs)
+	parallelResumes, parallelErrs := newClient(&parallelHookCalls, WithNormalizationConcurrency(4)).ParseDocuments(context.TODO(), docs)
+	require.Equal(t, sequentialResumes, parallelResumes)
+	require.Len(t, parallelErrs, len(sequentialErrs))
+	for i, err := range sequentialErrs {
+		if err == nil {
+			require.Nil(t, parallelErrs[i])
+			continue
+		}
+		require.EqualError(t, parallelErrs[i], err.Error())
+	}
+	require.Equal(t, "DOCUMENT 0", parallelResumes[0].FirstName)
+	require.ErrorIs(t, parallelErrs[3], ErrGatewayTimeout)
+	require.Nil(t, parallelResumes[5])
+	require.EqualError(t, parallelErrs[5], "normalizing resume: applying normalizer 0: invalid first name")
+	require.Equal(t, int32(len(docs)-2), parallelHookCalls.Load())
+	require.Equal(t, sequentialHookCalls.Load(), parallelHookCalls.Load())
+}
+
+func TestParseDocumentsWithNormalizationConcurrencyPipelined(t *testing.T) {
+	normalizing := make(chan struct{})
+	var normalizingOnce sync.Once
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body parseDocumentRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		doc, _ := base64.StdEncoding.DecodeString(body.Base64Data)
+		if string(doc) == "last" {
+			// The last document is only fetched once
+			// the others are being normalized.
+			select {
+			case <-normalizing:
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Resume{FirstName: string(doc)})
+	}))
+	defer svr.Close()
+	docs := make([][]byte, 0, 2*parallelNormalizationThreshold)
+	for i := 0; i < 2*parallelNormalizationThreshold-1; i++ {
+		docs = append(docs, []byte(fmt.Sprintf("document %d", i)))
+	}
+	docs = append(docs, []byte("last"))
+	var normalized atomic.Int32
+	var done []int
+	var normalizedAtDone []int32
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithNormalizationConcurrency(2),
+		WithNormalizers(func(resume *Resume) error {
+			normalizingOnce.Do(func() { close(normalizing) })
+			resume.FirstName = strings.ToUpper(resume.FirstName)
+			normalized.Add(1)
+			return nil
+		}),
+		WithBatchProgress(func(d, total int) {
+			done = append(done, d)
+			normalizedAtDone = append(normalizedAtDone, normalized.Load())
+		}),
+	)
+	resumes, errs := client.ParseDocuments(context.TODO(), docs)
+	for i, err := range errs {
+		require.Nil(t, err)
+		require.Equal(t, strings.ToUpper(string(docs[i])), resumes[i].FirstName)
+	}
+	require.Len(t, done, len(docs))
+	for i := range done {
+		// Documents are reported complete only once normalized.
+		require.Equal(t, i+1, done[i])
+		require.GreaterOrEqual(t, normalizedAtDone[i], int32(done[i]))
+	}
+}
+
+// staticHttpClient responds to every request with the same body, after
+// the latency, if any. Unlike resumeHttpClientMock, it is safe for
+// concurrent use.
+type staticHttpClient struct {
+	httpclient.Client
+	body    []byte
+	latency time.Duration
+}
+
+func (c *staticHttpClient) SendRequest(req *http.Request) (*http.Response, error) {
+	time.Sleep(c.latency)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(c.body)),
+	}, nil
+}
+
+func (c *staticHttpClient) SendRequestAndUnmarshallJsonResponse(req *http.Request, v any) (*http.Response, error) {
+	time.Sleep(c.latency)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+	return resp, json.Unmarshal(c.body, v)
+}
+
+func BenchmarkParseDocumentsNormalization(b *testing.B) {
+	skills := make([]Skill, 0, 200)
+	for i := 0; i < 200; i++ {
+		skills = append(skills, Skill{Name: fmt.Sprintf("  Skill   %d  ", i%50), NumMonths: i})
+	}
+	body, err := json.Marshal(Resume{
+		FirstName: "  Morgana  ",
+		Summary:   strings.Repeat("Postdoctoral   researcher \n", 200),
+		Emails:    []string{"Favero.Morgana@gmail.com", "favero.morgana@gmail.com"},
+		Skills:    skills,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	docs := make([][]byte, 4*parallelNormalizationThreshold)
+	benchmarks := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "sequential",
+		},
+		{
+			name:    "concurrent",
+			options: []Option{WithNormalizationConcurrency(runtime.GOMAXPROCS(0))},
+		},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			client := newResumeParsingServiceClient(append(bm.options,
+				WithNormalizeWhitespace(),
+				WithEmailNormalization(),
+				WithNormalizers(DedupSkills),
+			))
+			// Responses take about as long as the normalization of a few
+			// resumes, so that fetches and normalization overlap.
+			client.httpClient = &staticHttpClient{body: body, latency: 5 * time.Millisecond}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				client.ParseDocuments(context.TODO(), docs)
+			}
+		})
+	}
+}
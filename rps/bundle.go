@@ -0,0 +1,199 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BundleError is returned by ParseDocumentBundle when some of the
+// documents in the bundle could not be parsed. Errors is keyed by
+// the index of the failed document.
+type BundleError struct {
+	Total  int
+	Errors map[int]error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *BundleError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	messages := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		messages = append(messages, fmt.Sprintf("[%d]: %v", i, e.Errors[i]))
+	}
+	return fmt.Sprintf("failed to parse %d of %d documents: %s",
+		len(e.Errors), e.Total, strings.Join(messages, "; "))
+}
+
+// marshalParseDocumentBundleRequest encodes the documents
+// and marshals the request body.
+func (r *resumeParsingServiceClient) marshalParseDocumentBundleRequest(docs [][]byte) ([]byte, error) {
+	if err := r.validateDocuments(docs...); err != nil {
+		return nil, err
+	}
+	bundleRequest := &parseDocumentBundleRequest{
+		Documents: make([]parseDocumentRequest, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		likelyScanned := r.scannedPDFDetection && looksLikeScannedPDF(doc)
+		bundleRequest.Documents = append(bundleRequest.Documents, *r.newParseDocumentRequest(likelyScanned))
+	}
+	if err := r.checkBodySize(bundleRequest, docs...); err != nil {
+		return nil, err
+	}
+	for i, doc := range docs {
+		bundleRequest.Documents[i].Base64Data = r.base64Encoding.EncodeToString(doc)
+	}
+	j, err := jsonMarshal(bundleRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling parse document bundle request")
+	}
+	return j, nil
+}
+
+// ParseDocumentBundle sends all documents in a single request and
+// returns the parsed resumes in the same order. When some of the
+// documents fail to parse, the resumes for the successful ones
+// are still returned, along with a *BundleError holding the
+// per-index errors. Failed indexes are nil.
+func (r *resumeParsingServiceClient) ParseDocumentBundle(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error) {
+	if err := r.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer r.lifecycle.end()
+	call := r.newCallOptions(options)
+	start := time.Now()
+	j, err := r.marshalParseDocumentBundleRequest(docs)
+	call.metadata.EncodeDuration = time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	req, err := r.newRequest(ctx, call, "api/parse/bundle", j)
+	if err != nil {
+		return nil, err
+	}
+	results, resp, err := r.sendRequestAndDecodeBundleResults(req)
+	call.metadata.observeResponse(resp, err)
+	if err != nil {
+		return nil, classifyRequestError(errors.Wrap(err, "performing request"), call.metadata.StatusCode)
+	}
+	defer resp.Body.Close()
+	if len(results) != len(docs) {
+		return nil, errors.Errorf("expected %d results, got %d", len(docs), len(results))
+	}
+	return r.bundleResumes(results)
+}
+
+// sendRequestAndDecodeBundleResults sends the request and decodes the
+// results of the bundle. JSON results are decoded on the pool of workers
+// set through WithDecodeConcurrency, if any, preserving their order.
+func (r *resumeParsingServiceClient) sendRequestAndDecodeBundleResults(req *http.Request) ([]parseDocumentBundleResult, *http.Response, error) {
+	if r.decodeWorkers <= 0 || !r.acceptsJsonResponse() {
+		var results []parseDocumentBundleResult
+		_, resp, err := r.sendRequestAndDecodeResponse(req, r.localizingDates(&results, localizeBundleDates), nil, nil, false)
+		return results, resp, err
+	}
+	var elements []json.RawMessage
+	splitter := map[string]Decoder{jsonContentType: DecoderFunc(splitJSONArray)}
+	_, resp, err := r.sendRequestAndDecodeResponse(req, &elements, nil, splitter, false)
+	if err != nil {
+		return nil, resp, err
+	}
+	results, err := r.decodeBundleResults(elements)
+	return results, resp, errors.Wrap(err, "decoding response")
+}
+
+// splitJSONArray splits the JSON array read from rd into its elements,
+// set to v, a *[]json.RawMessage, without decoding them.
+func splitJSONArray(rd io.Reader, v any) error {
+	decoder := json.NewDecoder(rd)
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	elements := v.(*[]json.RawMessage)
+	if token == nil {
+		*elements = nil
+		return nil
+	}
+	if token != json.Delim('[') {
+		return errors.Errorf("expected an array, got %v", token)
+	}
+	for decoder.More() {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return err
+		}
+		*elements = append(*elements, element)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// decodeBundleResults decodes the raw results of a bundle concurrently,
+// running at most as many decodes at a time as there are decode workers.
+// Each result is decoded by the decoder of JSON responses, within the
+// decode timeout, if any.
+func (r *resumeParsingServiceClient) decodeBundleResults(elements []json.RawMessage) ([]parseDocumentBundleResult, error) {
+	decoder, err := r.decoderFor(jsonContentType)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]parseDocumentBundleResult, len(elements))
+	errs := make([]error, len(elements))
+	runConcurrently(len(elements), r.decodeWorkers, func(i int) {
+		decoded := r.decodeTargetFor(&results[i])
+		dst := r.localizingDates(decoded, localizeBundleResultDates)
+		if errs[i] = r.decode(decoder, bytes.NewReader(elements[i]), nil, dst); errs[i] == nil {
+			setDecodeTarget(&results[i], decoded)
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding result %d", i)
+		}
+	}
+	return results, nil
+}
+
+// bundleResumes normalizes the successfully parsed resumes
+// and collects the errors of the failed ones.
+func (r *resumeParsingServiceClient) bundleResumes(results []parseDocumentBundleResult) ([]*Resume, error) {
+	resumes := make([]*Resume, len(results))
+	bundleErr := &BundleError{Total: len(results), Errors: make(map[int]error)}
+	for i, result := range results {
+		if result.Error != "" || result.Resume == nil {
+			bundleErr.Errors[i] = errors.Errorf("parsing document: %s", result.Error)
+			continue
+		}
+		if err := r.checkDetectedLanguage(result.Resume); err != nil {
+			bundleErr.Errors[i] = err
+			continue
+		}
+		if err := r.normalize(result.Resume); err != nil {
+			bundleErr.Errors[i] = err
+			continue
+		}
+		if err := r.checkViable(result.Resume); err != nil {
+			bundleErr.Errors[i] = err
+			continue
+		}
+		resumes[i] = result.Resume
+	}
+	if len(bundleErr.Errors) > 0 {
+		return resumes, bundleErr
+	}
+	return resumes, nil
+}
@@ -0,0 +1,248 @@
+package rps
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentBundle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		responseBody   string
+		expectedOutput []*Resume
+		expectedError  error
+	}{
+		{
+			name:         "happy path",
+			responseBody: `[{"resume":{"first_name":"Morgana"}},{"resume":{"first_name":"John"}}]`,
+			expectedOutput: []*Resume{
+				{FirstName: "Morgana"},
+				{FirstName: "John"},
+			},
+		},
+		{
+			name:         "partial failure",
+			responseBody: `[{"resume":{"first_name":"Morgana"}},{"error":"unsupported document"}]`,
+			expectedOutput: []*Resume{
+				{FirstName: "Morgana"},
+				nil,
+			},
+			expectedError: errors.New("failed to parse 1 of 2 documents: [1]: parsing document: unsupported document"),
+		},
+		{
+			name:          "unexpected number of results",
+			responseBody:  `[{"resume":{"first_name":"Morgana"}}]`,
+			expectedError: errors.New("expected 2 results, got 1"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/parse/bundle", r.URL.Path)
+				require.Equal(t, "TOKEN", r.Header.Get("token"))
+				var body parseDocumentBundleRequest
+				require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, []parseDocumentRequest{{Base64Data: "Zmlyc3Q="}, {Base64Data: "c2Vjb25k"}}, body.Documents)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.responseBody))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			output, err := client.ParseDocumentBundle(context.TODO(), [][]byte{[]byte("first"), []byte("second")})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+			}
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestParseDocumentBundlePartialFailure(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"error":"corrupted file"},{"resume":{"emails":["A@B.COM"]}}]`))
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithEmailNormalization())
+	output, err := client.ParseDocumentBundle(context.TODO(), [][]byte{{}, {}})
+	var bundleErr *BundleError
+	require.True(t, errors.As(err, &bundleErr))
+	require.Equal(t, 2, bundleErr.Total)
+	require.Len(t, bundleErr.Errors, 1)
+	require.EqualError(t, bundleErr.Errors[0], "parsing document: corrupted file")
+	require.Nil(t, output[0])
+	require.Equal(t, []string{"a@b.com"}, output[1].Emails)
+}
+
+func TestParseDocumentBundleWithDecodeConcurrency(t *testing.T) {
+	testCases := []struct {
+		name          string
+		results       func(docs []parseDocumentRequest) string
+		expectedError error
+	}{
+		{
+			name: "results match the serial decode",
+			results: func(docs []parseDocumentRequest) string {
+				results := make([]string, 0, len(docs))
+				for i, doc := range docs {
+					if i%10 == 3 {
+						results = append(results, `{"error":"unsupported document"}`)
+						continue
+					}
+					results = append(results, fmt.Sprintf(`{"resume":{"first_name":%q,"skills":[{"name":"Go","num_months":%d}]}}`, doc.Base64Data, i))
+				}
+				return "[" + strings.Join(results, ",") + "]"
+			},
+		},
+		{
+			name: "malformed result",
+			results: func(docs []parseDocumentRequest) string {
+				return `[{"resume":{"first_name":"Morgana"}},{"resume":{"first_name":7}}]`
+			},
+			expectedError: errors.New("performing request: decoding response: decoding result 1: json: cannot unmarshal number into Go struct field parseDocumentBundleResult.resume.first_name of type string"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body parseDocumentBundleRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.results(body.Documents)))
+			}))
+			defer svr.Close()
+			docs := make([][]byte, 0, 50)
+			for i := 0; i < 50; i++ {
+				docs = append(docs, []byte(fmt.Sprintf("document %d", i)))
+			}
+			if tc.expectedError != nil {
+				docs = docs[:2]
+			}
+			serialOutput, serialErr := NewResumeParsingServiceClient("TOKEN", svr.URL).ParseDocumentBundle(context.TODO(), docs)
+			output, err := NewResumeParsingServiceClient("TOKEN", svr.URL, WithDecodeConcurrency(4)).ParseDocumentBundle(context.TODO(), docs)
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				require.Error(t, serialErr)
+				return
+			}
+			require.Equal(t, serialOutput, output)
+			require.EqualError(t, err, serialErr.Error())
+			require.Len(t, output, len(docs))
+			require.Equal(t, base64.StdEncoding.EncodeToString(docs[0]), output[0].FirstName)
+		})
+	}
+}
+
+func TestParseDocumentBundleWithDecodeConcurrencyAndDecoder(t *testing.T) {
+	testCases := []struct {
+		name              string
+		decodeDelay       time.Duration
+		expectedDecodings int32
+		expectedError     error
+	}{
+		{
+			name:              "each result is decoded by the decoder",
+			expectedDecodings: 3,
+		},
+		{
+			name:          "decoding a result exceeding the timeout",
+			decodeDelay:   time.Second,
+			expectedError: ErrDecodeTimeout,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[{"resume":{"first_name":"Morgana"}}, {"resume":{"first_name":"Nimue"}}, {"error":"unsupported document"}]`))
+			}))
+			defer svr.Close()
+			var decodings atomic.Int32
+			decoder := DecoderFunc(func(r io.Reader, v any) error {
+				decodings.Add(1)
+				time.Sleep(tc.decodeDelay)
+				return json.NewDecoder(r).Decode(v)
+			})
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithDecodeConcurrency(2),
+				WithDecoder("application/json", decoder),
+				WithDecodeTimeout(100*time.Millisecond),
+			)
+			output, err := client.ParseDocumentBundle(context.TODO(), [][]byte{{}, {}, {}})
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.EqualError(t, err, "failed to parse 1 of 3 documents: [2]: parsing document: unsupported document")
+			require.Equal(t, tc.expectedDecodings, decodings.Load())
+			require.Equal(t, "Morgana", output[0].FirstName)
+			require.Equal(t, "Nimue", output[1].FirstName)
+			require.Nil(t, output[2])
+		})
+	}
+}
+
+func BenchmarkParseDocumentBundleDecode(b *testing.B) {
+	results := make([]parseDocumentBundleResult, 0, 256)
+	for i := 0; i < 256; i++ {
+		skills := make([]Skill, 0, 50)
+		for j := 0; j < 50; j++ {
+			skills = append(skills, Skill{Name: fmt.Sprintf("Skill %d", j), NumMonths: j})
+		}
+		results = append(results, parseDocumentBundleResult{Resume: &Resume{
+			FirstName: "Morgana",
+			RawText:   strings.Repeat("Postdoctoral researcher in electrophysiology. ", 100),
+			Skills:    skills,
+		}})
+	}
+	body, err := json.Marshal(results)
+	if err != nil {
+		b.Fatal(err)
+	}
+	docs := make([][]byte, len(results))
+	benchmarks := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "serial",
+		},
+		{
+			name:    "concurrent",
+			options: []Option{WithDecodeConcurrency(runtime.GOMAXPROCS(0))},
+		},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			client := newResumeParsingServiceClient(bm.options)
+			client.httpClient = &staticHttpClient{body: body}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.ParseDocumentBundle(context.TODO(), docs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
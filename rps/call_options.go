@@ -0,0 +1,67 @@
+package rps
+
+// CallOption represents an option that applies to a single call,
+// without affecting the client.
+type CallOption func(*callOptions)
+
+// callOptions holds the options of a single call.
+type callOptions struct {
+	baseUrl  string
+	metadata *CallMetadata
+
+	// metadataRequested is set when the metadata of the call
+	// was requested through WithCallMetadata.
+	metadataRequested bool
+
+	// eventStream, when set, requests the service to stream the
+	// parse as text/event-stream, and decodes such a response.
+	eventStream *eventStreamDecoder
+
+	// deferPostProcessing, when set, is handed the post-processing of
+	// the parsed resume (normalization and response hook) instead of
+	// it being run, so that a batch can run it on its own workers.
+	deferPostProcessing func(postProcess func() error)
+}
+
+// newCallOptions applies the call options on top of
+// the client configuration.
+func (r *resumeParsingServiceClient) newCallOptions(options []CallOption) *callOptions {
+	call := &callOptions{
+		baseUrl:  r.rioParseBaseUrl,
+		metadata: new(CallMetadata),
+	}
+	for _, option := range options {
+		option(call)
+	}
+	if r.httpsUpgrade {
+		call.baseUrl = r.upgradeToHTTPS(call.baseUrl)
+	}
+	call.metadata.ClientName = r.clientName
+	return call
+}
+
+// decoders returns the decoders specific to the call, keyed by
+// content type, which take precedence over the ones of the client.
+func (c *callOptions) decoders() map[string]Decoder {
+	if c.eventStream == nil {
+		return nil
+	}
+	return map[string]Decoder{eventStreamContentType: c.eventStream}
+}
+
+// WithBaseUrlOverride overrides the base URL of the service for a
+// single call, e.g. to route part of the traffic to a canary. It is
+// upgraded to HTTPS like the base URL through WithHTTPSUpgrade.
+func WithBaseUrlOverride(baseUrl string) CallOption {
+	return func(c *callOptions) {
+		c.baseUrl = baseUrl
+	}
+}
+
+// withDeferredPostProcessing hands the post-processing
+// of the parsed resume to deferPostProcessing.
+func withDeferredPostProcessing(deferPostProcessing func(postProcess func() error)) CallOption {
+	return func(c *callOptions) {
+		c.deferPostProcessing = deferPostProcessing
+	}
+}
@@ -0,0 +1,23 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithBaseUrlOverride(t *testing.T) {
+	client := newClientWithMock(t, nil)
+	mock, ok := client.httpClient.(*resumeHttpClientMock)
+	require.True(t, ok)
+
+	_, err := client.ParseDocument(context.TODO(), []byte{}, WithBaseUrlOverride("https://canary.example.com"))
+	require.Nil(t, err)
+	require.Equal(t, "https://canary.example.com/api/parse", mock.Req.URL.String())
+
+	_, err = client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.Equal(t, "URL/api/parse", mock.Req.URL.String())
+	require.Equal(t, "URL", client.rioParseBaseUrl)
+}
@@ -0,0 +1,76 @@
+package rps
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCapturedResponses bounds the number of captured responses
+	// kept on disk. The oldest ones are removed first.
+	maxCapturedResponses = 100
+
+	// capturedResponsePattern matches the files of captured responses.
+	capturedResponsePattern = "response-*.json"
+
+	// capturedResponseTimeLayout is the layout of the timestamps
+	// of captured responses, which sort chronologically.
+	capturedResponseTimeLayout = "20060102T150405.000000000Z"
+)
+
+// responseCapture writes raw responses to a directory.
+type responseCapture struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// capture writes the raw response, with its personal information
+// masked, to a timestamped file, then removes the oldest captured
+// responses beyond maxCapturedResponses. Failures are ignored, as
+// capturing is a debugging aid that must not fail the call. A nil
+// capture does nothing.
+func (c *responseCapture) capture(raw []byte) {
+	if c == nil {
+		return
+	}
+	redacted, err := redactJSON(raw)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.write(redacted); err != nil {
+		return
+	}
+	c.rotate()
+}
+
+// write writes the response to a new timestamped file.
+func (c *responseCapture) write(response []byte) error {
+	timestamp := time.Now().UTC().Format(capturedResponseTimeLayout)
+	f, err := os.CreateTemp(c.dir, "response-"+timestamp+"-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(response); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotate removes the oldest captured responses
+// beyond maxCapturedResponses.
+func (c *responseCapture) rotate() {
+	paths, err := filepath.Glob(filepath.Join(c.dir, capturedResponsePattern))
+	if err != nil || len(paths) <= maxCapturedResponses {
+		return
+	}
+	sort.Strings(paths)
+	for _, path := range paths[:len(paths)-maxCapturedResponses] {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,59 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithResponseCaptureDir(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana","emails":["morgana@avalon.org"]}`))
+	}))
+	defer svr.Close()
+	dir := t.TempDir()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithResponseCaptureDir(dir))
+
+	resume, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.Equal(t, []string{"morgana@avalon.org"}, resume.Emails)
+	paths, err := filepath.Glob(filepath.Join(dir, "response-*.json"))
+	require.Nil(t, err)
+	require.Len(t, paths, 1)
+	captured, err := os.ReadFile(paths[0])
+	require.Nil(t, err)
+	require.JSONEq(t, `{"first_name":"Morgana","emails":["[REDACTED]"]}`, string(captured))
+}
+
+func TestResponseCaptureRotation(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxCapturedResponses; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("response-20000101T000000.%09dZ-0.json", i))
+		require.Nil(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	}
+	capture := &responseCapture{dir: dir}
+
+	capture.capture([]byte(`{"first_name":"Morgana"}`))
+	paths, err := filepath.Glob(filepath.Join(dir, "response-*.json"))
+	require.Nil(t, err)
+	require.Len(t, paths, maxCapturedResponses)
+	require.NotContains(t, paths, filepath.Join(dir, "response-20000101T000000.000000000Z-0.json"))
+	require.Contains(t, paths, filepath.Join(dir, "response-20000101T000000.000000001Z-0.json"))
+}
+
+func TestResponseCaptureOfNonJSONResponse(t *testing.T) {
+	dir := t.TempDir()
+	capture := &responseCapture{dir: dir}
+
+	capture.capture([]byte("Morgana"))
+	paths, err := filepath.Glob(filepath.Join(dir, "response-*.json"))
+	require.Nil(t, err)
+	require.Empty(t, paths)
+}
@@ -0,0 +1,55 @@
+package rps
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrChecksumMismatch is returned when the response body does not
+// match the checksum sent by the service, e.g. when truncated.
+var ErrChecksumMismatch = errors.New("response checksum mismatch")
+
+// checksumHeaders lists the headers the service may send a checksum
+// of the response body through, along with how it is computed.
+var checksumHeaders = []struct {
+	name     string
+	checksum func(body []byte) string
+}{
+	{
+		// Base64-encoded MD5 digest, as per RFC 1864.
+		name: "Content-MD5",
+		checksum: func(body []byte) string {
+			sum := md5.Sum(body)
+			return base64.StdEncoding.EncodeToString(sum[:])
+		},
+	},
+	{
+		// Hex-encoded SHA-256 digest.
+		name: "X-Checksum",
+		checksum: func(body []byte) string {
+			sum := sha256.Sum256(body)
+			return hex.EncodeToString(sum[:])
+		},
+	},
+}
+
+// verifyChecksum returns ErrChecksumMismatch if the body does not
+// match any of the checksum headers of the response.
+func verifyChecksum(resp *http.Response, body []byte) error {
+	for _, header := range checksumHeaders {
+		expected := resp.Header.Get(header.name)
+		if expected == "" {
+			continue
+		}
+		if !strings.EqualFold(expected, header.checksum(body)) {
+			return errors.Wrapf(ErrChecksumMismatch, "verifying %s", header.name)
+		}
+	}
+	return nil
+}
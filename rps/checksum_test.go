@@ -0,0 +1,93 @@
+package rps
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithResponseChecksumVerification(t *testing.T) {
+	body := "{\"first_name\":\"Morgana\"}\n"
+	md5Sum := md5.Sum([]byte(body))
+	sha256Sum := sha256.Sum256([]byte(body))
+	testCases := []struct {
+		name           string
+		options        []Option
+		headers        map[string]string
+		expectedOutput *Resume
+		expectedError  error
+	}{
+		{
+			name:           "no verification by default",
+			headers:        map[string]string{"Content-MD5": "bWlzbWF0Y2g="},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:           "no checksum header",
+			options:        []Option{WithResponseChecksumVerification()},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:           "matching Content-MD5",
+			options:        []Option{WithResponseChecksumVerification()},
+			headers:        map[string]string{"Content-MD5": base64.StdEncoding.EncodeToString(md5Sum[:])},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:          "mismatching Content-MD5",
+			options:       []Option{WithResponseChecksumVerification()},
+			headers:       map[string]string{"Content-MD5": "bWlzbWF0Y2g="},
+			expectedError: errors.New("performing request: verifying Content-MD5: response checksum mismatch"),
+		},
+		{
+			name:           "matching X-Checksum",
+			options:        []Option{WithResponseChecksumVerification()},
+			headers:        map[string]string{"X-Checksum": strings.ToUpper(hex.EncodeToString(sha256Sum[:]))},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:    "mismatching X-Checksum",
+			options: []Option{WithResponseChecksumVerification()},
+			headers: map[string]string{
+				"Content-MD5": base64.StdEncoding.EncodeToString(md5Sum[:]),
+				"X-Checksum":  hex.EncodeToString(md5Sum[:]),
+			},
+			expectedError: errors.New("performing request: verifying X-Checksum: response checksum mismatch"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				for name, value := range tc.headers {
+					w.Header().Set(name, value)
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			output, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				require.ErrorIs(t, err, ErrChecksumMismatch)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package rps
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// numberLiteral returns the number held by a JSON value, either a
+// number or a string holding one, as the service occasionally quotes
+// numbers. It returns false for null and empty strings, which leave
+// the decoded value unchanged.
+func numberLiteral(data []byte) (string, bool, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		return string(data), true, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", false, err
+	}
+	s = string(bytes.TrimSpace([]byte(s)))
+	return s, s != "", nil
+}
+
+// lenientInt is an int decoded from either a JSON number or a string.
+type lenientInt int
+
+// UnmarshalJSON decodes a number or a string holding one.
+func (i *lenientInt) UnmarshalJSON(data []byte) error {
+	s, ok, err := numberLiteral(data)
+	if err != nil || !ok {
+		return err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return errors.Errorf("invalid integer %s", data)
+	}
+	*i = lenientInt(n)
+	return nil
+}
+
+// lenientFloat is a float64 decoded from either a JSON number or a string.
+type lenientFloat float64
+
+// UnmarshalJSON decodes a number or a string holding one.
+func (f *lenientFloat) UnmarshalJSON(data []byte) error {
+	s, ok, err := numberLiteral(data)
+	if err != nil || !ok {
+		return err
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return errors.Errorf("invalid number %s", data)
+	}
+	*f = lenientFloat(n)
+	return nil
+}
+
+// UnmarshalJSON decodes a skill, accepting its
+// number of months as a number or a string.
+func (s *Skill) UnmarshalJSON(data []byte) error {
+	type skill Skill
+	decoded := struct {
+		*skill
+		NumMonths lenientInt `json:"num_months"`
+	}{skill: &skill{}}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = Skill(*decoded.skill)
+	s.NumMonths = int(decoded.NumMonths)
+	return nil
+}
+
+// Confidences are extraction confidences by field name.
+type Confidences map[string]float64
+
+// UnmarshalJSON decodes the confidences, accepting
+// each of them as a number or a string.
+func (c *Confidences) UnmarshalJSON(data []byte) error {
+	var decoded map[string]lenientFloat
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded == nil {
+		*c = nil
+		return nil
+	}
+	*c = make(Confidences, len(decoded))
+	for field, confidence := range decoded {
+		(*c)[field] = float64(confidence)
+	}
+	return nil
+}
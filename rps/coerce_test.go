@@ -0,0 +1,76 @@
+package rps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLenientNumericFields(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput Resume
+		expectedError  error
+	}{
+		{
+			name:  "numbers",
+			input: `{"skills":[{"name":"Research","num_months":31}],"positions":[{"title":"Sorceress","confidence":0.8}],"educations":[{"degree":"BSc","confidence":0.5}]}`,
+			expectedOutput: Resume{
+				Skills:     []Skill{{Name: "Research", NumMonths: 31}},
+				Positions:  []Position{{Title: "Sorceress", Confidence: 0.8}},
+				Educations: []Education{{Degree: "BSc", Confidence: 0.5}},
+			},
+		},
+		{
+			name:  "quoted numbers",
+			input: `{"skills":[{"name":"Research","num_months":"31"}],"positions":[{"title":"Sorceress","confidence":"0.8"}],"educations":[{"degree":"BSc","confidence":" 0.5 "}]}`,
+			expectedOutput: Resume{
+				Skills:     []Skill{{Name: "Research", NumMonths: 31}},
+				Positions:  []Position{{Title: "Sorceress", Confidence: 0.8}},
+				Educations: []Education{{Degree: "BSc", Confidence: 0.5}},
+			},
+		},
+		{
+			name:  "null and empty values",
+			input: `{"skills":[{"name":"Research","num_months":null},{"name":"Teamwork","num_months":""}],"positions":[{"title":"Sorceress","confidence":""}],"educations":[{"degree":"BSc","confidence":null}]}`,
+			expectedOutput: Resume{
+				Skills:     []Skill{{Name: "Research"}, {Name: "Teamwork"}},
+				Positions:  []Position{{Title: "Sorceress", Confidence: defaultConfidence}},
+				Educations: []Education{{Degree: "BSc", Confidence: defaultConfidence}},
+			},
+		},
+		{
+			name:          "invalid field confidence",
+			input:         `{"confidence":{"name":"sure"}}`,
+			expectedError: errors.New(`invalid number "sure"`),
+		},
+		{
+			name:          "invalid integer",
+			input:         `{"skills":[{"name":"Research","num_months":"thirty-one"}]}`,
+			expectedError: errors.New(`invalid integer "thirty-one"`),
+		},
+		{
+			name:          "invalid number",
+			input:         `{"positions":[{"title":"Sorceress","confidence":"high"}]}`,
+			expectedError: errors.New(`invalid number "high"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output Resume
+			err := json.Unmarshal([]byte(tc.input), &output)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			require.Nil(t, tc.expectedError)
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package rps
+
+import "strings"
+
+// maxCollapsedPositionsGap is the maximum number of months between the
+// end of a position and the start of the next one for both to be
+// considered contiguous fragments of a single role.
+const maxCollapsedPositionsGap = 1
+
+// samePositionRole reports whether both positions have the same,
+// non-empty, title and organization, ignoring case and surrounding
+// whitespace.
+func samePositionRole(p, another *Position) bool {
+	title, organization := strings.TrimSpace(p.Title), strings.TrimSpace(p.Organization)
+	return title != "" && organization != "" &&
+		strings.EqualFold(title, strings.TrimSpace(another.Title)) &&
+		strings.EqualFold(organization, strings.TrimSpace(another.Organization))
+}
+
+// contiguousPositions reports whether the later position starts when
+// the earlier one ends, or within maxCollapsedPositionsGap months.
+// Undated and overlapping positions are not contiguous, as they may
+// well be distinct roles.
+func contiguousPositions(earlier, later *Position) bool {
+	if earlier.StartDate == nil || earlier.EndDate == nil || later.StartDate == nil {
+		return false
+	}
+	return !later.StartDate.Before(*earlier.EndDate) &&
+		!later.StartDate.After(earlier.EndDate.AddDate(0, maxCollapsedPositionsGap, 0)) &&
+		!earlier.StartDate.After(*earlier.EndDate)
+}
+
+// mergePositions merges two contiguous fragments of a role into one,
+// spanning from the start of the earlier one to the end of the later
+// one. The fields of the later one take precedence, and the
+// descriptions are joined if they differ.
+func mergePositions(earlier, later Position) Position {
+	merged := later
+	merged.StartDate = earlier.StartDate
+	if merged.TitleNormalized == "" {
+		merged.TitleNormalized = earlier.TitleNormalized
+	}
+	if merged.ManagementLevel == "" {
+		merged.ManagementLevel = earlier.ManagementLevel
+	}
+	if merged.Location == (Location{}) {
+		merged.Location = earlier.Location
+	}
+	switch {
+	case merged.Description == "":
+		merged.Description = earlier.Description
+	case earlier.Description != "" && earlier.Description != merged.Description:
+		merged.Description = earlier.Description + "\n" + merged.Description
+	}
+	merged.Confidence = min(earlier.Confidence, later.Confidence)
+	return merged
+}
+
+// collapseAdjacentPositions merges the consecutive positions that are
+// contiguous fragments of a single role, i.e. with the same title and
+// organization and contiguous dates, whichever order they are listed in.
+func collapseAdjacentPositions(resume *Resume) {
+	if len(resume.Positions) < 2 {
+		return
+	}
+	collapsed := resume.Positions[:1]
+	for _, position := range resume.Positions[1:] {
+		last := &collapsed[len(collapsed)-1]
+		switch {
+		case !samePositionRole(last, &position):
+			collapsed = append(collapsed, position)
+		case contiguousPositions(last, &position):
+			*last = mergePositions(*last, position)
+		case contiguousPositions(&position, last):
+			*last = mergePositions(position, *last)
+		default:
+			collapsed = append(collapsed, position)
+		}
+	}
+	resume.Positions = collapsed
+}
@@ -0,0 +1,122 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithCollapseAdjacentPositions(t *testing.T) {
+	testCases := []struct {
+		name              string
+		options           []Option
+		positions         []Position
+		expectedPositions []Position
+	}{
+		{
+			name: "positions are kept by default",
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 6), EndDate: date(2021, 3)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 6), EndDate: date(2021, 3)},
+			},
+		},
+		{
+			name:    "contiguous fragments are merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6), Description: "Built the API.", Confidence: 0.9},
+				{Title: "engineer ", Organization: "ACME", StartDate: date(2019, 7), Description: "Led the migration.", Confidence: 0.8},
+			},
+			expectedPositions: []Position{
+				{Title: "engineer ", Organization: "ACME", StartDate: date(2018, 1), Description: "Built the API.\nLed the migration.", Confidence: 0.8},
+			},
+		},
+		{
+			name:    "fragments listed most recent first are merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2021, 4), EndDate: date(2023, 1), Location: Location{City: "Boston"}},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 6), EndDate: date(2021, 3), Location: Location{City: "Austin"}},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2023, 1), Location: Location{City: "Boston"}},
+			},
+		},
+		{
+			name:    "gap longer than a month is not merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2020, 1), EndDate: date(2021, 3)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2020, 1), EndDate: date(2021, 3)},
+			},
+		},
+		{
+			name:    "overlapping positions are not merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2020, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 1), EndDate: date(2021, 3)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2020, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 1), EndDate: date(2021, 3)},
+			},
+		},
+		{
+			name:    "different titles are not merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Senior Engineer", Organization: "Acme", StartDate: date(2019, 6)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Senior Engineer", Organization: "Acme", StartDate: date(2019, 6)},
+			},
+		},
+		{
+			name:    "undated positions are not merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme"},
+				{Title: "Engineer", Organization: "Acme"},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme"},
+				{Title: "Engineer", Organization: "Acme"},
+			},
+		},
+		{
+			name:    "non-adjacent positions are not merged",
+			options: []Option{WithCollapseAdjacentPositions()},
+			positions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Consultant", Organization: "Initech", StartDate: date(2019, 6), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 6), EndDate: date(2021, 3)},
+			},
+			expectedPositions: []Position{
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2018, 1), EndDate: date(2019, 6)},
+				{Title: "Consultant", Organization: "Initech", StartDate: date(2019, 6), EndDate: date(2019, 6)},
+				{Title: "Engineer", Organization: "Acme", StartDate: date(2019, 6), EndDate: date(2021, 3)},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{Positions: tc.positions}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedPositions, resume.Positions)
+		})
+	}
+}
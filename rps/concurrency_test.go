@@ -0,0 +1,90 @@
+package rps
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseDocumentConcurrently shares a client between many goroutines,
+// to be run with the race detector (make test-race).
+func TestParseDocumentConcurrently(t *testing.T) {
+	var seen sync.Map
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req parseDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		name, err := base64.StdEncoding.DecodeString(req.Base64Data)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Fails the first request of each document,
+		// so that calls are retried concurrently.
+		if _, ok := seen.LoadOrStore(string(name), true); !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", fmt.Sprintf("%q", name))
+		_ = json.NewEncoder(w).Encode(Resume{
+			FirstName: string(name),
+			Emails:    []string{" Morgana@Example.com "},
+			Skills:    []Skill{{Name: "  Go  ", NumMonths: 12}, {Name: "go", NumMonths: 24}},
+			Location:  Location{State: "Pennsylvania", CountryCode: "US"},
+		})
+	}))
+	defer svr.Close()
+
+	var hooks atomic.Int64
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithMaxRetries(3),
+		WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithEmailNormalization(),
+		WithNormalizeWhitespace(),
+		WithSkillCanonicalization(map[string]string{"go": "Golang"}),
+		WithUSStateNormalization(),
+		WithETagCache(NewMemoryETagCache()),
+		WithHeader("X-Tenant-Id", "camelot"),
+		WithResponseHook(func(resume *Resume, raw []byte) {
+			hooks.Add(1)
+		}),
+	)
+
+	const calls = 50
+	var wg sync.WaitGroup
+	resumes := make([]*Resume, calls)
+	errs := make([]error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Documents are parsed twice, to share the cached responses.
+			document := []byte(fmt.Sprintf("Morgana %d", i%(calls/2)))
+			resumes[i], errs[i] = client.ParseDocument(context.TODO(), document)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < calls; i++ {
+		require.NoError(t, errs[i])
+		require.Equal(t, fmt.Sprintf("Morgana %d", i%(calls/2)), resumes[i].FirstName)
+		require.Equal(t, []string{"morgana@example.com"}, resumes[i].Emails)
+		require.Equal(t, []Skill{{Name: "Golang", NumMonths: 36}}, resumes[i].Skills)
+		require.Equal(t, "PA", resumes[i].Location.State)
+	}
+	require.Equal(t, int64(calls), hooks.Load())
+}
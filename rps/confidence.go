@@ -0,0 +1,75 @@
+package rps
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// defaultConfidence is the confidence assumed for entries
+// decoded without a confidence score.
+const defaultConfidence = 1.0
+
+// UnmarshalJSON decodes a position, defaulting its confidence to 1.0
+// when absent, and accepting it as a number or a string.
+func (p *Position) UnmarshalJSON(data []byte) error {
+	type position Position
+	decoded := struct {
+		*position
+		Confidence lenientFloat `json:"confidence"`
+	}{position: &position{}, Confidence: defaultConfidence}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*p = Position(*decoded.position)
+	p.Confidence = float64(decoded.Confidence)
+	return nil
+}
+
+// UnmarshalJSON decodes an education, defaulting its confidence to 1.0
+// when absent, and accepting it as a number or a string.
+func (e *Education) UnmarshalJSON(data []byte) error {
+	type education Education
+	decoded := struct {
+		*education
+		Confidence lenientFloat `json:"confidence"`
+	}{education: &education{}, Confidence: defaultConfidence}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*e = Education(*decoded.education)
+	e.Confidence = float64(decoded.Confidence)
+	return nil
+}
+
+// filterByConfidence drops the positions and educations
+// whose confidence is below the given threshold.
+func filterByConfidence(resume *Resume, minConfidence float64) {
+	positions := resume.Positions[:0]
+	for _, position := range resume.Positions {
+		if position.Confidence >= minConfidence {
+			positions = append(positions, position)
+		}
+	}
+	resume.Positions = positions
+	educations := resume.Educations[:0]
+	for _, education := range resume.Educations {
+		if education.Confidence >= minConfidence {
+			educations = append(educations, education)
+		}
+	}
+	resume.Educations = educations
+}
+
+// LowConfidenceFields returns the fields whose extraction confidence is
+// below the threshold, in alphabetical order, e.g. to route uncertain
+// parses to review. It returns nil if the field confidence is absent.
+func (r *Resume) LowConfidenceFields(threshold float64) []string {
+	var fields []string
+	for field, confidence := range r.FieldConfidence {
+		if confidence < threshold {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,139 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeConfidence(t *testing.T) {
+	testCases := []struct {
+		name                         string
+		input                        string
+		expectedPositionConfidences  []float64
+		expectedEducationConfidences []float64
+	}{
+		{
+			name:                         "confidence present",
+			input:                        `{"positions":[{"title":"a","confidence":0.4},{"title":"b","confidence":0}],"educations":[{"degree":"c","confidence":0.9}]}`,
+			expectedPositionConfidences:  []float64{0.4, 0},
+			expectedEducationConfidences: []float64{0.9},
+		},
+		{
+			name:                         "confidence absent",
+			input:                        `{"positions":[{"title":"a"}],"educations":[{"degree":"c"}]}`,
+			expectedPositionConfidences:  []float64{1},
+			expectedEducationConfidences: []float64{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resume Resume
+			require.Nil(t, json.Unmarshal([]byte(tc.input), &resume))
+			positionConfidences := make([]float64, 0, len(resume.Positions))
+			for _, position := range resume.Positions {
+				positionConfidences = append(positionConfidences, position.Confidence)
+			}
+			educationConfidences := make([]float64, 0, len(resume.Educations))
+			for _, education := range resume.Educations {
+				educationConfidences = append(educationConfidences, education.Confidence)
+			}
+			require.Equal(t, tc.expectedPositionConfidences, positionConfidences)
+			require.Equal(t, tc.expectedEducationConfidences, educationConfidences)
+		})
+	}
+}
+
+func TestDecodeConfidenceError(t *testing.T) {
+	var resume Resume
+	require.NotNil(t, json.Unmarshal([]byte(`{"positions":[{"confidence":"high"}]}`), &resume))
+	require.NotNil(t, json.Unmarshal([]byte(`{"educations":[{"confidence":"high"}]}`), &resume))
+}
+
+func TestParseDocumentWithMinEntryConfidence(t *testing.T) {
+	testCases := []struct {
+		name               string
+		options            []Option
+		expectedPositions  []Position
+		expectedEducations []Education
+	}{
+		{
+			name:               "no filtering by default",
+			expectedPositions:  []Position{{Title: "a", Confidence: 0.2}, {Title: "b", Confidence: 0.8}},
+			expectedEducations: []Education{{Degree: "c", Confidence: 0.5}, {Degree: "d", Confidence: 1}},
+		},
+		{
+			name:               "entries below the threshold are dropped",
+			options:            []Option{WithMinEntryConfidence(0.5)},
+			expectedPositions:  []Position{{Title: "b", Confidence: 0.8}},
+			expectedEducations: []Education{{Degree: "c", Confidence: 0.5}, {Degree: "d", Confidence: 1}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				Positions:  []Position{{Title: "a", Confidence: 0.2}, {Title: "b", Confidence: 0.8}},
+				Educations: []Education{{Degree: "c", Confidence: 0.5}, {Degree: "d", Confidence: 1}},
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedPositions, resume.Positions)
+			require.Equal(t, tc.expectedEducations, resume.Educations)
+		})
+	}
+}
+
+func TestFieldConfidence(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		input                   string
+		threshold               float64
+		expectedFieldConfidence Confidences
+		expectedLowConfidence   []string
+	}{
+		{
+			name:      "confidence object",
+			input:     `{"first_name":"Morgana","confidence":{"first_name":0.99,"emails":0.4,"positions":0.7,"skills":0.2}}`,
+			threshold: 0.75,
+			expectedFieldConfidence: Confidences{
+				"first_name": 0.99,
+				"emails":     0.4,
+				"positions":  0.7,
+				"skills":     0.2,
+			},
+			expectedLowConfidence: []string{"emails", "positions", "skills"},
+		},
+		{
+			name:      "quoted confidences",
+			input:     `{"confidence":{"name":"0.9","emails":" 0.4 ","skills":0.2}}`,
+			threshold: 0.5,
+			expectedFieldConfidence: Confidences{
+				"name":   0.9,
+				"emails": 0.4,
+				"skills": 0.2,
+			},
+			expectedLowConfidence: []string{"emails", "skills"},
+		},
+		{
+			name:                    "every field above the threshold",
+			input:                   `{"confidence":{"first_name":0.99}}`,
+			threshold:               0.5,
+			expectedFieldConfidence: Confidences{"first_name": 0.99},
+		},
+		{
+			name:      "confidence object absent",
+			input:     `{"first_name":"Morgana"}`,
+			threshold: 0.5,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resume Resume
+			require.Nil(t, json.Unmarshal([]byte(tc.input), &resume))
+			require.Equal(t, tc.expectedFieldConfidence, resume.FieldConfidence)
+			require.Equal(t, tc.expectedLowConfidence, resume.LowConfidenceFields(tc.threshold))
+		})
+	}
+}
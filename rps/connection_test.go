@@ -0,0 +1,73 @@
+package rps
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithConnectionCloseAfterRequest(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		options                 []Option
+		expectedConnection      string
+		expectedReused          []bool
+		expectedClosedConnCount int
+	}{
+		{
+			name:           "connections are kept alive by default",
+			expectedReused: []bool{false, true},
+		},
+		{
+			name:                    "connections are closed after each request",
+			options:                 []Option{WithConnectionCloseAfterRequest()},
+			expectedConnection:      "close",
+			expectedReused:          []bool{false, false},
+			expectedClosedConnCount: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			closedConnCount := 0
+			svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.expectedConnection, r.Header.Get("Connection"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			svr.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateClosed {
+					mu.Lock()
+					closedConnCount++
+					mu.Unlock()
+				}
+			}
+			svr.Start()
+			var reused []bool
+			ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					reused = append(reused, info.Reused)
+				},
+			})
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			for range tc.expectedReused {
+				_, err := client.ParseDocument(ctx, []byte{})
+				require.Nil(t, err)
+			}
+			require.Equal(t, tc.expectedReused, reused)
+			require.Eventually(t, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return closedConnCount == tc.expectedClosedConnCount
+			}, time.Second, 10*time.Millisecond)
+			svr.Close()
+		})
+	}
+}
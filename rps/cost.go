@@ -0,0 +1,84 @@
+package rps
+
+import "regexp"
+
+// estimatedBytesPerPage is the size of a page assumed to estimate
+// the number of pages of documents whose pages cannot be counted.
+const estimatedBytesPerPage = 3000
+
+// pdfPageObject matches the page objects of a PDF document,
+// but not the page tree nodes (/Type /Pages).
+var pdfPageObject = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// pdfStreamContents matches the contents of the streams of a PDF
+// document, whose compressed bytes may contain page markers by chance.
+var pdfStreamContents = regexp.MustCompile(`(?s)stream\r?\n.*?endstream`)
+
+// Cost is the estimated cost of parsing a document.
+type Cost struct {
+	// Bytes is the size of the document.
+	Bytes int
+
+	// Pages is the number of pages of the document, counted for
+	// PDF documents and estimated from the size otherwise.
+	Pages int
+
+	// Credits is the cost computed by the pricing model.
+	Credits float64
+}
+
+// PricingModel computes the credits charged for parsing
+// a document of the given size and number of pages.
+type PricingModel func(bytes, pages int) float64
+
+// PerPagePricing returns a pricing model charging the given
+// credits per page. The default pricing model charges one.
+func PerPagePricing(creditsPerPage float64) PricingModel {
+	return func(bytes, pages int) float64 {
+		return float64(pages) * creditsPerPage
+	}
+}
+
+// defaultPricingModel is the pricing model used
+// unless one is set through WithPricingModel.
+var defaultPricingModel = PerPagePricing(1)
+
+// countPages returns the number of pages of the document, counting the
+// page objects of PDF documents, outside or inside compressed object
+// streams. Otherwise, or if none is found, the number is estimated
+// from the size of the document.
+func countPages(fileContents []byte) int {
+	if isPDF(fileContents) {
+		objects := pdfStreamContents.ReplaceAll(fileContents, nil)
+		pages := len(pdfPageObject.FindAllIndex(objects, -1))
+		for _, stream := range inflatedStreams(fileContents) {
+			pages += len(pdfPageObject.FindAllIndex(stream, -1))
+		}
+		if pages > 0 {
+			return pages
+		}
+	}
+	return (len(fileContents) + estimatedBytesPerPage - 1) / estimatedBytesPerPage
+}
+
+// estimateCost estimates the cost of parsing the document
+// with the given pricing model.
+func estimateCost(fileContents []byte, pricingModel PricingModel) Cost {
+	pages := countPages(fileContents)
+	return Cost{
+		Bytes:   len(fileContents),
+		Pages:   pages,
+		Credits: pricingModel(len(fileContents), pages),
+	}
+}
+
+// EstimateCost estimates the cost of parsing the document before
+// submitting it, with the pricing model set through WithPricingModel,
+// so that callers can budget their usage.
+func (r *resumeParsingServiceClient) EstimateCost(fileContents []byte) Cost {
+	pricingModel := r.pricingModel
+	if pricingModel == nil {
+		pricingModel = defaultPricingModel
+	}
+	return estimateCost(fileContents, pricingModel)
+}
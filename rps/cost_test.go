@@ -0,0 +1,83 @@
+package rps
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// compressedPagesPDF returns a PDF whose page objects
+// are inside a compressed object stream.
+func compressedPagesPDF(t *testing.T, pages int) []byte {
+	t.Helper()
+	var stream bytes.Buffer
+	w := zlib.NewWriter(&stream)
+	_, _ = w.Write([]byte("<< /Type /Pages /Count 3 >>"))
+	for i := 0; i < pages; i++ {
+		_, _ = w.Write([]byte("<< /Type /Page /Parent 2 0 R >>"))
+	}
+	require.Nil(t, w.Close())
+	pdf := []byte("%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /Filter /FlateDecode >>\nstream\n")
+	pdf = append(pdf, stream.Bytes()...)
+	return append(pdf, []byte("\nendstream\nendobj\n%%EOF\n")...)
+}
+
+func TestEstimateCost(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		fileContents   []byte
+		expectedOutput Cost
+	}{
+		{
+			name:         "empty document",
+			fileContents: []byte{},
+		},
+		{
+			name:           "small document",
+			fileContents:   []byte("resume"),
+			expectedOutput: Cost{Bytes: 6, Pages: 1, Credits: 1},
+		},
+		{
+			name:           "cost scales with size",
+			fileContents:   bytes.Repeat([]byte("a"), 2*estimatedBytesPerPage+1),
+			expectedOutput: Cost{Bytes: 6001, Pages: 3, Credits: 3},
+		},
+		{
+			name:           "pdf pages are counted",
+			fileContents:   []byte("%PDF-1.4\n<< /Type /Pages >>\n<< /Type /Page >>\n<< /Type/Page >>\n%%EOF\n"),
+			expectedOutput: Cost{Pages: 2, Credits: 2},
+		},
+		{
+			name:           "pdf pages inside a compressed object stream are counted",
+			fileContents:   compressedPagesPDF(t, 3),
+			expectedOutput: Cost{Pages: 3, Credits: 3},
+		},
+		{
+			name:           "pricing model is injected",
+			options:        []Option{WithPricingModel(PerPagePricing(0.5))},
+			fileContents:   bytes.Repeat([]byte("a"), 4*estimatedBytesPerPage),
+			expectedOutput: Cost{Bytes: 12000, Pages: 4, Credits: 2},
+		},
+		{
+			name: "pricing model is given the size",
+			options: []Option{WithPricingModel(func(bytes, pages int) float64 {
+				return float64(bytes) / 1000
+			})},
+			fileContents:   bytes.Repeat([]byte("a"), 1500),
+			expectedOutput: Cost{Bytes: 1500, Pages: 1, Credits: 1.5},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			cost := client.EstimateCost(tc.fileContents)
+			if tc.expectedOutput.Bytes == 0 {
+				tc.expectedOutput.Bytes = len(tc.fileContents)
+			}
+			require.Equal(t, tc.expectedOutput, cost)
+		})
+	}
+}
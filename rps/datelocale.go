@@ -0,0 +1,155 @@
+package rps
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// slashedDate matches a date written with slashes, e.g. 03/04/2015,
+// whose order of month and day depends on the locale.
+var slashedDate = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)
+
+// monthFirstRegions are the regions writing the month before the day.
+var monthFirstRegions = map[string]bool{"US": true, "PH": true, "FM": true, "MH": true, "PW": true}
+
+// dayFirstLocale reports whether dates are written day first in the
+// locale, e.g. it-IT or en-GB, rather than month first, e.g. en-US.
+// Locales without a region are day first, except English.
+func dayFirstLocale(locale string) bool {
+	subtags := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) == 0 {
+		return false
+	}
+	for _, subtag := range subtags[1:] {
+		if len(subtag) == 2 {
+			return !monthFirstRegions[strings.ToUpper(subtag)]
+		}
+	}
+	return !strings.EqualFold(subtags[0], "en")
+}
+
+// parseSlashedDate parses a date written with slashes, reading the day
+// first if dayFirst is set, or the month first otherwise, unless only
+// the other order is a valid date.
+func parseSlashedDate(s string, dayFirst bool) (*time.Time, error) {
+	match := slashedDate.FindStringSubmatch(s)
+	if match == nil {
+		return nil, errors.Errorf("invalid date %q", s)
+	}
+	month, _ := strconv.Atoi(match[1])
+	day, _ := strconv.Atoi(match[2])
+	year, _ := strconv.Atoi(match[3])
+	if dayFirst {
+		month, day = day, month
+	}
+	if month > 12 {
+		month, day = day, month
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || day < 1 || date.Day() != day {
+		return nil, errors.Errorf("invalid date %q", s)
+	}
+	return &date, nil
+}
+
+// localizedDateKeys are the keys of the resume whose entries have
+// start and end dates, possibly written with slashes.
+var localizedDateKeys = []string{"positions", "educations"}
+
+// localizingDates decodes a response into target once its dates
+// written with slashes are rewritten in RFC 3339 by localize, in
+// the order of the locale set through WithDateLocale.
+type localizingDates struct {
+	dayFirst bool
+	localize func(data []byte, dayFirst bool) ([]byte, error)
+	target   any
+}
+
+// localizingDates returns the value to decode a response into, which
+// is target itself unless a date locale is set, in which case the dates
+// are rewritten by localize, e.g. localizeDates for a resume.
+func (r *resumeParsingServiceClient) localizingDates(target any, localize func(data []byte, dayFirst bool) ([]byte, error)) any {
+	if r.dateLocale == "" {
+		return target
+	}
+	return &localizingDates{dayFirst: dayFirstLocale(r.dateLocale), localize: localize, target: target}
+}
+
+// UnmarshalJSON rewrites the dates written with slashes in RFC 3339,
+// then decodes the result into the target.
+func (l *localizingDates) UnmarshalJSON(data []byte) error {
+	localized, err := l.localize(data, l.dayFirst)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(localized, l.target)
+}
+
+// localizeDates rewrites the dates of the positions and educations of
+// the resume written with slashes in RFC 3339, reading the day first if
+// dayFirst is set. Anything that is not shaped as expected is left as
+// is, for the decoding of the resume to report.
+func localizeDates(resume []byte, dayFirst bool) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(resume, &fields); err != nil {
+		return resume, nil
+	}
+	for _, key := range localizedDateKeys {
+		var entries []map[string]json.RawMessage
+		if err := json.Unmarshal(fields[key], &entries); err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			for _, dateKey := range []string{"start_date", "end_date"} {
+				var s string
+				if err := json.Unmarshal(entry[dateKey], &s); err != nil || !slashedDate.MatchString(s) {
+					continue
+				}
+				date, err := parseSlashedDate(s, dayFirst)
+				if err != nil {
+					return nil, err
+				}
+				entry[dateKey], _ = json.Marshal(date)
+			}
+		}
+		fields[key], _ = json.Marshal(entries)
+	}
+	return json.Marshal(fields)
+}
+
+// localizeBundleResultDates rewrites the dates of
+// the resume of a bundle result, if any.
+func localizeBundleResultDates(result []byte, dayFirst bool) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil || fields["resume"] == nil {
+		return result, nil
+	}
+	resume, err := localizeDates(fields["resume"], dayFirst)
+	if err != nil {
+		return nil, err
+	}
+	fields["resume"] = resume
+	return json.Marshal(fields)
+}
+
+// localizeBundleDates rewrites the dates of
+// the resumes of the results of a bundle.
+func localizeBundleDates(results []byte, dayFirst bool) ([]byte, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(results, &elements); err != nil {
+		return results, nil
+	}
+	for i, element := range elements {
+		localized, err := localizeBundleResultDates(element, dayFirst)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = localized
+	}
+	return json.Marshal(elements)
+}
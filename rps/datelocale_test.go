@@ -0,0 +1,156 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDayFirstLocale(t *testing.T) {
+	testCases := []struct {
+		locale           string
+		expectedDayFirst bool
+	}{
+		{locale: "", expectedDayFirst: false},
+		{locale: "en-US", expectedDayFirst: false},
+		{locale: "en_us", expectedDayFirst: false},
+		{locale: "fil-PH", expectedDayFirst: false},
+		{locale: "en", expectedDayFirst: false},
+		{locale: "en-GB", expectedDayFirst: true},
+		{locale: "it-IT", expectedDayFirst: true},
+		{locale: "it", expectedDayFirst: true},
+		{locale: "sr-Latn-RS", expectedDayFirst: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.locale, func(t *testing.T) {
+			require.Equal(t, tc.expectedDayFirst, dayFirstLocale(tc.locale))
+		})
+	}
+}
+
+func TestParseDocumentWithDateLocale(t *testing.T) {
+	body := []byte(`{
+		"positions": [{"start_date": "03/04/2015", "end_date": "25/12/2018"}],
+		"educations": [{"start_date": "2002-01-01T00:00:00Z", "end_date": "07/08/2008"}]
+	}`)
+	testCases := []struct {
+		name           string
+		body           []byte
+		options        []Option
+		expectedOutput map[string]string
+		expectedError  string
+	}{
+		{
+			name:          "slashed dates are rejected by default",
+			expectedError: `parsing time "03/04/2015"`,
+		},
+		{
+			name:    "month first locale",
+			options: []Option{WithDateLocale("en-US")},
+			expectedOutput: map[string]string{
+				"positions[0].start_date":  "2015-03-04",
+				"positions[0].end_date":    "2018-12-25",
+				"educations[0].start_date": "2002-01-01",
+				"educations[0].end_date":   "2008-07-08",
+			},
+		},
+		{
+			name:    "day first locale",
+			options: []Option{WithDateLocale("it-IT")},
+			expectedOutput: map[string]string{
+				"positions[0].start_date":  "2015-04-03",
+				"positions[0].end_date":    "2018-12-25",
+				"educations[0].start_date": "2002-01-01",
+				"educations[0].end_date":   "2008-08-07",
+			},
+		},
+		{
+			name:    "day first locale with model version 2",
+			options: []Option{WithDateLocale("it-IT"), WithModelVersion(ModelV2)},
+			expectedOutput: map[string]string{
+				"positions[0].start_date":  "2015-04-03",
+				"positions[0].end_date":    "2018-12-25",
+				"educations[0].start_date": "2002-01-01",
+				"educations[0].end_date":   "2008-08-07",
+			},
+		},
+		{
+			name:    "day first locale in envelope",
+			body:    []byte(`{"data": {"positions": [{"start_date": "03/04/2015"}]}}`),
+			options: []Option{WithDateLocale("it-IT"), WithResponseEnvelope("data")},
+			expectedOutput: map[string]string{
+				"positions[0].start_date": "2015-04-03",
+				"positions[0].end_date":   "",
+			},
+		},
+		{
+			name: "day first locale before other normalizers",
+			body: []byte(`{"positions": [{"start_date": "03/04/2015"}, {"start_date": "10/03/2015"}]}`),
+			options: []Option{
+				WithMaxPositions(1),
+				WithDateLocale("en-GB"),
+			},
+			expectedOutput: map[string]string{
+				"positions[0].start_date": "2015-04-03",
+				"positions[0].end_date":   "",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newResumeParsingServiceClient(tc.options)
+			client.httpClient = &staticHttpClient{body: body}
+			if tc.body != nil {
+				client.httpClient = &staticHttpClient{body: tc.body}
+			}
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume.FormatDates("2006-01-02"))
+		})
+	}
+}
+
+func TestParseDocumentBundleWithDateLocale(t *testing.T) {
+	body := []byte(`[
+		{"resume": {"positions": [{"start_date": "03/04/2015"}]}},
+		{"error": "unreadable document"}
+	]`)
+	testCases := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "sequential decoding",
+		},
+		{
+			name:    "concurrent decoding",
+			options: []Option{WithDecodeConcurrency(2)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newResumeParsingServiceClient(append(tc.options, WithDateLocale("it-IT")))
+			client.httpClient = &staticHttpClient{body: body}
+			resumes, err := client.ParseDocumentBundle(context.TODO(), [][]byte{[]byte("a"), []byte("b")})
+			var bundleErr *BundleError
+			require.ErrorAs(t, err, &bundleErr)
+			require.Len(t, bundleErr.Errors, 1)
+			require.Equal(t, map[string]string{
+				"positions[0].start_date": "2015-04-03",
+				"positions[0].end_date":   "",
+			}, resumes[0].FormatDates("2006-01-02"))
+		})
+	}
+}
+
+func TestParseDocumentWithInvalidSlashedDate(t *testing.T) {
+	client := newResumeParsingServiceClient([]Option{WithDateLocale("it-IT")})
+	client.httpClient = &staticHttpClient{body: []byte(`{"positions":[{"start_date":"31/31/2015"}]}`)}
+	_, err := client.ParseDocument(context.TODO(), []byte{})
+	require.ErrorContains(t, err, `invalid date "31/31/2015"`)
+}
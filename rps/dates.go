@@ -0,0 +1,80 @@
+package rps
+
+import (
+	"fmt"
+	"time"
+)
+
+// formatDate formats the date with the given layout,
+// returning an empty string for nil dates.
+func formatDate(d *time.Time, layout string) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format(layout)
+}
+
+// StartDateString returns the start date formatted with the
+// given layout, or an empty string if there is none.
+func (p *Position) StartDateString(layout string) string {
+	return formatDate(p.StartDate, layout)
+}
+
+// EndDateString returns the end date formatted with the
+// given layout, or an empty string if there is none.
+func (p *Position) EndDateString(layout string) string {
+	return formatDate(p.EndDate, layout)
+}
+
+// StartDateString returns the start date formatted with the
+// given layout, or an empty string if there is none.
+func (e *Education) StartDateString(layout string) string {
+	return formatDate(e.StartDate, layout)
+}
+
+// EndDateString returns the end date formatted with the
+// given layout, or an empty string if there is none.
+func (e *Education) EndDateString(layout string) string {
+	return formatDate(e.EndDate, layout)
+}
+
+// FormatDates returns every date of the resume formatted with the given
+// layout, keyed by its JSON path, e.g. "positions[0].start_date".
+// Nil dates are mapped to empty strings.
+func (r *Resume) FormatDates(layout string) map[string]string {
+	dates := make(map[string]string, 2*(len(r.Positions)+len(r.Educations)))
+	for i := range r.Positions {
+		dates[fmt.Sprintf("positions[%d].start_date", i)] = r.Positions[i].StartDateString(layout)
+		dates[fmt.Sprintf("positions[%d].end_date", i)] = r.Positions[i].EndDateString(layout)
+	}
+	for i := range r.Educations {
+		dates[fmt.Sprintf("educations[%d].start_date", i)] = r.Educations[i].StartDateString(layout)
+		dates[fmt.Sprintf("educations[%d].end_date", i)] = r.Educations[i].EndDateString(layout)
+	}
+	return dates
+}
+
+// inLocation returns the date in the given location, or nil for nil
+// dates. A copy is returned, as dates may be shared between resumes.
+func inLocation(d *time.Time, loc *time.Location) *time.Time {
+	if d == nil {
+		return nil
+	}
+	converted := d.In(loc)
+	return &converted
+}
+
+// dateTimezoneNormalizer returns a normalizer converting
+// every date of the resume to the given location.
+func dateTimezoneNormalizer(loc *time.Location) func(resume *Resume) {
+	return func(resume *Resume) {
+		for i := range resume.Positions {
+			resume.Positions[i].StartDate = inLocation(resume.Positions[i].StartDate, loc)
+			resume.Positions[i].EndDate = inLocation(resume.Positions[i].EndDate, loc)
+		}
+		for i := range resume.Educations {
+			resume.Educations[i].StartDate = inLocation(resume.Educations[i].StartDate, loc)
+			resume.Educations[i].EndDate = inLocation(resume.Educations[i].EndDate, loc)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package rps
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateStrings(t *testing.T) {
+	position := &Position{StartDate: date(2015, time.November)}
+	require.Equal(t, "Nov 2015", position.StartDateString("Jan 2006"))
+	require.Equal(t, "", position.EndDateString("Jan 2006"))
+
+	education := &Education{EndDate: date(2008, time.January)}
+	require.Equal(t, "", education.StartDateString("2006-01"))
+	require.Equal(t, "2008-01", education.EndDateString("2006-01"))
+}
+
+func TestFormatDates(t *testing.T) {
+	testCases := []struct {
+		name           string
+		resume         *Resume
+		expectedOutput map[string]string
+	}{
+		{
+			name:           "no dates",
+			resume:         &Resume{},
+			expectedOutput: map[string]string{},
+		},
+		{
+			name: "populated and nil dates",
+			resume: &Resume{
+				Positions: []Position{
+					{StartDate: date(2015, time.November)},
+					{StartDate: date(2013, time.March), EndDate: date(2015, time.October)},
+				},
+				Educations: []Education{
+					{EndDate: date(2008, time.January)},
+				},
+			},
+			expectedOutput: map[string]string{
+				"positions[0].start_date":  "11/2015",
+				"positions[0].end_date":    "",
+				"positions[1].start_date":  "03/2013",
+				"positions[1].end_date":    "10/2015",
+				"educations[0].start_date": "",
+				"educations[0].end_date":   "01/2008",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, tc.resume.FormatDates("01/2006"))
+		})
+	}
+}
+
+func TestParseDocumentWithDateTimezone(t *testing.T) {
+	eastern := time.FixedZone("EST", -5*60*60)
+	testCases := []struct {
+		name             string
+		options          []Option
+		expectedLocation *time.Location
+		expectedDay      string
+	}{
+		{
+			name:             "dates as decoded by default",
+			expectedLocation: time.UTC,
+			expectedDay:      "2015-11-01",
+		},
+		{
+			name:             "nil location is ignored",
+			options:          []Option{WithDateTimezone(nil)},
+			expectedLocation: time.UTC,
+			expectedDay:      "2015-11-01",
+		},
+		{
+			name:             "UTC dates converted to the location",
+			options:          []Option{WithDateTimezone(eastern)},
+			expectedLocation: eastern,
+			expectedDay:      "2015-10-31",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2015, time.November, 1, 0, 0, 0, 0, time.UTC)
+			client := newClientWithMock(t, &Resume{
+				Positions:  []Position{{StartDate: &start, EndDate: &start}},
+				Educations: []Education{{StartDate: &start}},
+			}, tc.options...)
+
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			for _, d := range []*time.Time{
+				resume.Positions[0].StartDate,
+				resume.Positions[0].EndDate,
+				resume.Educations[0].StartDate,
+			} {
+				require.True(t, d.Equal(start))
+				require.Equal(t, tc.expectedLocation, d.Location())
+				require.Equal(t, tc.expectedDay, d.Format("2006-01-02"))
+			}
+			require.Nil(t, resume.Educations[0].EndDate)
+		})
+	}
+}
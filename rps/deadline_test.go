@@ -0,0 +1,115 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithDeadlinePropagationHeader(t *testing.T) {
+	testCases := []struct {
+		name            string
+		options         []Option
+		timeout         time.Duration
+		expectedMinimum int64
+		expectedMaximum int64
+		expectedHeader  bool
+	}{
+		{
+			name:    "no header by default",
+			timeout: time.Minute,
+		},
+		{
+			name:    "no header without a deadline",
+			options: []Option{WithDeadlinePropagationHeader()},
+		},
+		{
+			name:            "remaining time until the deadline",
+			options:         []Option{WithDeadlinePropagationHeader()},
+			timeout:         time.Minute,
+			expectedHeader:  true,
+			expectedMinimum: 59000,
+			expectedMaximum: 60000,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var header string
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get("X-Deadline")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			ctx := context.TODO()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			_, err := client.ParseDocument(ctx, []byte{})
+			require.Nil(t, err)
+			if !tc.expectedHeader {
+				require.Empty(t, header)
+				return
+			}
+			remaining, err := strconv.ParseInt(header, 10, 64)
+			require.Nil(t, err)
+			require.GreaterOrEqual(t, remaining, tc.expectedMinimum)
+			require.LessOrEqual(t, remaining, tc.expectedMaximum)
+		})
+	}
+}
+
+func TestParseDocumentWithDeadlinePropagationHeaderOnRetry(t *testing.T) {
+	var mu sync.Mutex
+	var headers []int64
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining, _ := strconv.ParseInt(r.Header.Get("X-Deadline"), 10, 64)
+		mu.Lock()
+		headers = append(headers, remaining)
+		attempt := len(headers)
+		mu.Unlock()
+		if attempt == 1 {
+			time.Sleep(300 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithDeadlinePropagationHeader(),
+		WithMaxRetries(1),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			return resp != nil && resp.StatusCode == http.StatusServiceUnavailable, nil
+		}),
+	)
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Minute)
+	defer cancel()
+	_, err := client.ParseDocument(ctx, []byte{})
+	require.Nil(t, err)
+	require.Len(t, headers, 2)
+	// The retry sends the time left then, not the one of the first attempt.
+	require.LessOrEqual(t, headers[1], headers[0]-300)
+}
+
+func TestSetDeadlineHeaderAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(-time.Second))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "URL", nil)
+	require.Nil(t, err)
+	setDeadlineHeader(req, 0)
+	require.Equal(t, "0", req.Header.Get("X-Deadline"))
+}
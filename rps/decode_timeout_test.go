@@ -0,0 +1,85 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithDecodeTimeout(t *testing.T) {
+	testCases := []struct {
+		name          string
+		decodeDelay   time.Duration
+		expectedName  string
+		expectedError error
+	}{
+		{
+			name:         "decoding within the timeout",
+			expectedName: "Morgana",
+		},
+		{
+			name:          "decoding exceeding the timeout",
+			decodeDelay:   time.Second,
+			expectedError: ErrDecodeTimeout,
+		},
+	}
+	originalJsonDecoder := jsonDecoder
+	defer func() { jsonDecoder = originalJsonDecoder }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonDecoder = DecoderFunc(func(r io.Reader, v any) error {
+				time.Sleep(tc.decodeDelay)
+				return json.NewDecoder(r).Decode(v)
+			})
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithDecodeTimeout(50*time.Millisecond))
+			var resume Resume
+			start := time.Now()
+			err := client.ParseDocumentInto(context.TODO(), []byte{}, &resume)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				require.True(t, IsTimeout(err))
+				require.Less(t, time.Since(start), tc.decodeDelay)
+				// The aborted decoding does not write the target.
+				require.Equal(t, Resume{}, resume)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedName, resume.FirstName)
+		})
+	}
+}
+
+func TestParseDocumentWithDecodeTimeoutAndSlowBody(t *testing.T) {
+	aborted := make(chan bool, 1)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana",`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+			aborted <- true
+		case <-time.After(5 * time.Second):
+			aborted <- false
+			_, _ = w.Write([]byte(`"last_name":"Favero"}`))
+		}
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithDecodeTimeout(50*time.Millisecond))
+	start := time.Now()
+	_, err := client.ParseDocument(context.TODO(), []byte{})
+	require.ErrorIs(t, err, ErrDecodeTimeout)
+	require.Less(t, time.Since(start), time.Second)
+	// The reading of the body is aborted along with the decoding.
+	require.True(t, <-aborted)
+}
@@ -0,0 +1,204 @@
+package rps
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/TalentInc/resume-parsing-service-client/httpclient"
+	"github.com/pkg/errors"
+)
+
+const jsonContentType = "application/json"
+
+// Decoder decodes a response body of a given content type.
+type Decoder interface {
+	// Decode decodes the data read from r into v.
+	Decode(r io.Reader, v any) error
+}
+
+// DecoderFunc is an adapter to allow the use of ordinary
+// functions as decoders.
+type DecoderFunc func(r io.Reader, v any) error
+
+// Decode calls f(r, v).
+func (f DecoderFunc) Decode(r io.Reader, v any) error {
+	return f(r, v)
+}
+
+// jsonDecoder is the decoder used for JSON responses.
+var jsonDecoder = DecoderFunc(func(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+})
+
+// mediaType returns the media type of a Content-Type header
+// value, without its parameters.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mt
+}
+
+// decoderFor returns the decoder registered for the given
+// content type. JSON responses are always decodable, and a
+// missing content type is assumed to be JSON.
+func (r *resumeParsingServiceClient) decoderFor(contentType string) (Decoder, error) {
+	mt := mediaType(contentType)
+	if decoder, ok := r.decoders[mt]; ok {
+		return decoder, nil
+	}
+	if mt == jsonContentType || mt == "" {
+		return jsonDecoder, nil
+	}
+	return nil, errors.Errorf("no decoder registered for content type %q", mt)
+}
+
+// sendRequestAndDecodeResponse sends the request and decodes the
+// response into v. JSON is expected unless a different content type
+// was requested through WithAcceptEncoding, in which case the decoder
+// registered for the negotiated content type is used. The decoders
+// given, keyed by content type, take precedence over the registered ones.
+// The raw response body is returned as well when keepRaw is set, in
+// which case the body is read once, as it is decoded. For an event
+// stream, the raw body is the data of the complete event of the stream.
+// A 304 response to a request for a cached response is answered
+// from the cache.
+func (r *resumeParsingServiceClient) sendRequestAndDecodeResponse(req *http.Request, v any, cached *ETagEntry, decoders map[string]Decoder, keepRaw bool) ([]byte, *http.Response, error) {
+	if r.decodesJsonResponse() && cached == nil && len(decoders) == 0 && !keepRaw {
+		resp, err := r.httpClient.SendRequestAndUnmarshallJsonResponse(req, v)
+		return nil, resp, err
+	}
+	resp, err := r.httpClient.SendRequest(req)
+	if err != nil {
+		return nil, resp, err
+	}
+	defer resp.Body.Close()
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		decoder, err := r.decoderFor(cached.ContentType)
+		if err == nil {
+			err = r.decode(decoder, bytes.NewReader(cached.Body), resp.Body, v)
+		}
+		if err != nil {
+			return nil, resp, errors.Wrap(err, "decoding cached response")
+		}
+		return cached.Body, resp, nil
+	}
+	decoder, ok := decoders[mediaType(resp.Header.Get("Content-Type"))]
+	if !ok {
+		decoder, err = r.responseDecoderFor(resp)
+	}
+	if err != nil {
+		return nil, resp, errors.Wrap(err, "decoding response")
+	}
+	var raw bytes.Buffer
+	body := io.Reader(resp.Body)
+	if keepRaw || r.checksumVerification {
+		body = io.TeeReader(resp.Body, &raw)
+	}
+	err = r.decode(decoder, body, resp.Body, v)
+	if errors.Is(err, ErrDecodeTimeout) {
+		return nil, resp, errors.Wrap(err, "decoding response")
+	}
+	// Reads the body up to its end, so that the raw body is whole
+	// and the trailers, if any, are received.
+	if _, readErr := io.Copy(io.Discard, body); readErr != nil && err == nil {
+		return nil, resp, errors.Wrap(readErr, "reading response")
+	}
+	if r.checksumVerification {
+		if err := verifyChecksum(resp, raw.Bytes()); err != nil {
+			return raw.Bytes(), resp, err
+		}
+	}
+	if err != nil {
+		return raw.Bytes(), resp, errors.Wrap(err, "decoding response")
+	}
+	if stream, ok := decoder.(*eventStreamDecoder); ok {
+		return stream.complete, resp, nil
+	}
+	return raw.Bytes(), resp, nil
+}
+
+// decode decodes the response body read from rd into v, within the
+// decode timeout set through WithDecodeTimeout, if any. On timeout,
+// body, if any, is closed, which aborts the reading of the response,
+// and the decoding goes on in the background, hence v must not be used.
+func (r *resumeParsingServiceClient) decode(decoder Decoder, rd io.Reader, body io.Closer, v any) error {
+	if r.decodeTimeout <= 0 {
+		return decoder.Decode(rd, v)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- decoder.Decode(rd, v)
+	}()
+	timer := time.NewTimer(r.decodeTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		if body != nil {
+			body.Close()
+		}
+		return ErrDecodeTimeout
+	}
+}
+
+// decodeTargetFor returns the value to decode the response into: a new
+// value of the type of target when a decode timeout is set, so that
+// target is not written by an aborted decoding, or target itself.
+func (r *resumeParsingServiceClient) decodeTargetFor(target any) any {
+	if r.decodeTimeout <= 0 {
+		return target
+	}
+	return reflect.New(reflect.TypeOf(target).Elem()).Interface()
+}
+
+// setDecodeTarget sets target to the decoded value, if different.
+func setDecodeTarget(target, decoded any) {
+	if decoded != target {
+		reflect.ValueOf(target).Elem().Set(reflect.ValueOf(decoded).Elem())
+	}
+}
+
+// acceptsJsonResponse reports whether the response is expected to be
+// JSON, unless a different content type was requested.
+func (r *resumeParsingServiceClient) acceptsJsonResponse() bool {
+	return r.acceptContentType == "" || r.acceptContentType == jsonContentType
+}
+
+// decodesJsonResponse reports whether the response is expected to be
+// JSON and can be decoded by the HTTP client, which is not the case
+// when its exact bytes are needed, as by checksums, when it must be
+// decoded within a timeout or validated against a schema, or when a
+// decoder is registered for JSON.
+func (r *resumeParsingServiceClient) decodesJsonResponse() bool {
+	_, customDecoder := r.decoders[jsonContentType]
+	return r.acceptsJsonResponse() && !r.checksumVerification && r.decodeTimeout <= 0 &&
+		r.responseSchema == nil && !customDecoder
+}
+
+// responseDecoderFor returns the decoder to use for the response. When
+// JSON is expected, a response of another content type without a
+// registered decoder is reported as an unexpected content type, as
+// the HTTP client does.
+func (r *resumeParsingServiceClient) responseDecoderFor(resp *http.Response) (Decoder, error) {
+	contentType := resp.Header.Get("Content-Type")
+	decoder, err := r.decoderFor(contentType)
+	if err != nil && r.acceptsJsonResponse() {
+		return nil, errors.Wrapf(httpclient.ErrUnexpectedContentType, "%q", contentType)
+	}
+	return decoder, err
+}
+
+// keepsRawResponse reports whether the raw response body is needed
+// once decoded, e.g. by the response hook or the ETag cache.
+func (r *resumeParsingServiceClient) keepsRawResponse(call *callOptions) bool {
+	return r.etagCache != nil || r.responseCapture != nil || r.unmarshalInterceptor != nil ||
+		r.responseHook != nil || call.metadataRequested
+}
@@ -0,0 +1,117 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMsgpackDecoder pretends to decode a msgpack body, using
+// its raw contents as the first name.
+var fakeMsgpackDecoder = DecoderFunc(func(r io.Reader, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	resume, _ := v.(*Resume)
+	resume.FirstName = string(b)
+	return nil
+})
+
+func TestParseDocumentWithAcceptEncoding(t *testing.T) {
+	testCases := []struct {
+		name                string
+		options             []Option
+		responseContentType string
+		responseBody        string
+		expectedAccept      string
+		expectedOutput      *Resume
+		expectedError       error
+	}{
+		{
+			name:                "json by default",
+			responseContentType: "application/json",
+			responseBody:        `{"first_name":"Morgana"}`,
+			expectedOutput:      &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:                "explicit json",
+			options:             []Option{WithAcceptEncoding("application/json")},
+			responseContentType: "application/json",
+			responseBody:        `{"first_name":"Morgana"}`,
+			expectedAccept:      "application/json",
+			expectedOutput:      &Resume{FirstName: "Morgana"},
+		},
+		{
+			name: "registered decoder is used",
+			options: []Option{
+				WithAcceptEncoding("application/msgpack"),
+				WithDecoder("application/msgpack", fakeMsgpackDecoder),
+			},
+			responseContentType: "application/msgpack; charset=binary",
+			responseBody:        "Morgana",
+			expectedAccept:      "application/msgpack",
+			expectedOutput:      &Resume{FirstName: "Morgana"},
+		},
+		{
+			name: "json fallback when the service ignores the Accept header",
+			options: []Option{
+				WithAcceptEncoding("application/msgpack"),
+				WithDecoder("application/msgpack", fakeMsgpackDecoder),
+			},
+			responseContentType: "application/json; charset=utf-8",
+			responseBody:        `{"first_name":"Morgana"}`,
+			expectedAccept:      "application/msgpack",
+			expectedOutput:      &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:                "no decoder registered",
+			options:             []Option{WithAcceptEncoding("application/msgpack")},
+			responseContentType: "application/msgpack",
+			responseBody:        "Morgana",
+			expectedAccept:      "application/msgpack",
+			expectedError:       errors.New(`performing request: decoding response: no decoder registered for content type "application/msgpack"`),
+		},
+		{
+			name: "error when decoding",
+			options: []Option{
+				WithAcceptEncoding("application/msgpack"),
+				WithDecoder("application/msgpack", DecoderFunc(func(r io.Reader, v any) error {
+					return errors.New("random error")
+				})),
+			},
+			responseContentType: "application/msgpack",
+			responseBody:        "Morgana",
+			expectedAccept:      "application/msgpack",
+			expectedError:       errors.New("performing request: decoding response: random error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.expectedAccept, r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", tc.responseContentType)
+				_, _ = w.Write([]byte(tc.responseBody))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			output, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package rps
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// parseDocumentURL parses the URL of a hosted document, which
+// must be an absolute HTTP(S) URL for the service to fetch it.
+func parseDocumentURL(fileURL string) (*url.URL, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing document URL")
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("document URL %q is not an absolute HTTP(S) URL", fileURL)
+	}
+	return u, nil
+}
+
+// marshalParseDocumentURLRequest marshals the request
+// body of a document hosted at the given URL.
+func (r *resumeParsingServiceClient) marshalParseDocumentURLRequest(u *url.URL) ([]byte, error) {
+	j, err := jsonMarshal(&parseDocumentURLRequest{
+		URL: u.String(),
+		OCR: r.ocrFlag(false),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling parse document URL request")
+	}
+	return j, nil
+}
+
+// ParseDocumentFromURL sends the URL of a hosted resume document for the
+// service to fetch and parse, instead of the document itself. The URL is
+// validated before any request is sent. Options inspecting the document,
+// such as the ETag cache, the request validator or the scanned PDF
+// detection, do not apply.
+func (r *resumeParsingServiceClient) ParseDocumentFromURL(ctx context.Context, fileURL string, options ...CallOption) (*Resume, error) {
+	u, err := parseDocumentURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer r.lifecycle.end()
+	call := r.newCallOptions(options)
+	start := time.Now()
+	var resume Resume
+	err = r.parseDocumentFromURL(ctx, u, &resume, call)
+	r.observeLatency(time.Since(start), call.metadata.StatusCode)
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// parseDocumentFromURL sends the URL of a resume document for
+// parsing and decodes the response into target.
+func (r *resumeParsingServiceClient) parseDocumentFromURL(ctx context.Context, u *url.URL, target any, call *callOptions) error {
+	if err := r.requestQueue.wait(ctx); err != nil {
+		return errors.Wrap(err, "waiting in request queue")
+	}
+	start := time.Now()
+	j, err := r.marshalParseDocumentURLRequest(u)
+	call.metadata.EncodeDuration = time.Since(start)
+	if err != nil {
+		return err
+	}
+	req, err := r.newRequest(ctx, call, "api/parse", j)
+	if err != nil {
+		return err
+	}
+	return r.sendParseDocumentRequest(req, "", nil, target, call)
+}
@@ -0,0 +1,71 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentFromURL(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []Option
+		fileURL       string
+		expectedBody  map[string]any
+		expectedError error
+	}{
+		{
+			name:         "hosted document",
+			fileURL:      "https://files.example.com/resumes/morgana.pdf",
+			expectedBody: map[string]any{"url": "https://files.example.com/resumes/morgana.pdf"},
+		},
+		{
+			name:         "hosted document with OCR",
+			options:      []Option{WithOCR(true)},
+			fileURL:      "http://files.example.com/resumes/morgana.pdf?token=abc",
+			expectedBody: map[string]any{"url": "http://files.example.com/resumes/morgana.pdf?token=abc", "ocr": true},
+		},
+		{
+			name:          "malformed URL",
+			fileURL:       "://files.example.com",
+			expectedError: errors.New(`parsing document URL: parse "://files.example.com": missing protocol scheme`),
+		},
+		{
+			name:          "relative URL",
+			fileURL:       "resumes/morgana.pdf",
+			expectedError: errors.New(`document URL "resumes/morgana.pdf" is not an absolute HTTP(S) URL`),
+		},
+		{
+			name:          "unsupported scheme",
+			fileURL:       "ftp://files.example.com/resumes/morgana.pdf",
+			expectedError: errors.New(`document URL "ftp://files.example.com/resumes/morgana.pdf" is not an absolute HTTP(S) URL`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{FirstName: "Morgana"}, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			resume, err := client.ParseDocumentFromURL(context.TODO(), tc.fileURL)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				require.Nil(t, mock.Req)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, "Morgana", resume.FirstName)
+				var body map[string]any
+				require.Nil(t, json.NewDecoder(mock.Req.Body).Decode(&body))
+				require.Equal(t, tc.expectedBody, body)
+			}
+		})
+	}
+}
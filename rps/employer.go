@@ -0,0 +1,38 @@
+package rps
+
+// isCurrent reports whether the position is held currently,
+// i.e. it has started and has no end date.
+func (p *Position) isCurrent() bool {
+	return p.StartDate != nil && p.EndDate == nil
+}
+
+// moreRecentThan reports whether the position is more recent than
+// another one: current positions come first, then the latest start date.
+func (p *Position) moreRecentThan(another *Position) bool {
+	if p.isCurrent() != another.isCurrent() {
+		return p.isCurrent()
+	}
+	return moreRecent(p.StartDate, another.StartDate)
+}
+
+// MostRecentEmployer returns the organization of the most recent
+// position: a current one (without an end date) if any, otherwise the
+// one with the latest start date. Ties are broken by the order of the
+// positions. Positions without an organization are ignored, and false
+// is returned if there is none left.
+func (r *Resume) MostRecentEmployer() (string, bool) {
+	var mostRecent *Position
+	for i := range r.Positions {
+		position := &r.Positions[i]
+		if position.Organization == "" {
+			continue
+		}
+		if mostRecent == nil || position.moreRecentThan(mostRecent) {
+			mostRecent = position
+		}
+	}
+	if mostRecent == nil {
+		return "", false
+	}
+	return mostRecent.Organization, true
+}
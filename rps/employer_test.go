@@ -0,0 +1,78 @@
+package rps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMostRecentEmployer(t *testing.T) {
+	testCases := []struct {
+		name             string
+		positions        []Position
+		expectedEmployer string
+		expectedFound    bool
+	}{
+		{
+			name: "empty positions",
+		},
+		{
+			name:      "positions without organization",
+			positions: []Position{{Title: "Sorceress", StartDate: date(2020, 1)}},
+		},
+		{
+			name: "current position is preferred",
+			positions: []Position{
+				{Organization: "Avalon", StartDate: date(2021, 1), EndDate: date(2023, 1)},
+				{Organization: "Camelot", StartDate: date(2015, 1)},
+			},
+			expectedEmployer: "Camelot",
+			expectedFound:    true,
+		},
+		{
+			name: "latest start date among past positions",
+			positions: []Position{
+				{Organization: "Avalon", StartDate: date(2015, 1), EndDate: date(2018, 1)},
+				{Organization: "Camelot", StartDate: date(2018, 2), EndDate: date(2020, 1)},
+				{Organization: "Tintagel"},
+			},
+			expectedEmployer: "Camelot",
+			expectedFound:    true,
+		},
+		{
+			name: "latest start date among current positions",
+			positions: []Position{
+				{Organization: "Avalon", StartDate: date(2015, 1)},
+				{Organization: "Camelot", StartDate: date(2018, 2)},
+			},
+			expectedEmployer: "Camelot",
+			expectedFound:    true,
+		},
+		{
+			name: "ties are broken by order",
+			positions: []Position{
+				{Organization: "Avalon", StartDate: date(2018, 1)},
+				{Organization: "Camelot", StartDate: date(2018, 1)},
+			},
+			expectedEmployer: "Avalon",
+			expectedFound:    true,
+		},
+		{
+			name: "undated positions only",
+			positions: []Position{
+				{Organization: "Avalon"},
+				{Organization: "Camelot"},
+			},
+			expectedEmployer: "Avalon",
+			expectedFound:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resume := &Resume{Positions: tc.positions}
+			employer, found := resume.MostRecentEmployer()
+			require.Equal(t, tc.expectedEmployer, employer)
+			require.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package rps
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// envelopeMetaKey is the key holding the metadata
+// of an envelope-wrapped response.
+const envelopeMetaKey = "meta"
+
+// envelope decodes a JSON response wrapping its data under
+// a given key, e.g. {"data": {...}, "meta": {...}}.
+type envelope struct {
+	dataKey string
+	target  any
+	meta    json.RawMessage
+}
+
+// envelopeFor returns the value the response is decoded into,
+// which is target itself unless WithResponseEnvelope is set.
+func (r *resumeParsingServiceClient) envelopeFor(target any) (any, *envelope) {
+	if r.envelopeDataKey == "" {
+		return target, nil
+	}
+	env := &envelope{dataKey: r.envelopeDataKey, target: target}
+	return env, env
+}
+
+// UnmarshalJSON decodes the data key into the target
+// and keeps the metadata as is.
+func (e *envelope) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	data, ok := fields[e.dataKey]
+	if !ok {
+		return errors.Errorf("missing envelope key %q", e.dataKey)
+	}
+	e.meta = fields[envelopeMetaKey]
+	return json.Unmarshal(data, e.target)
+}
+
+// Meta returns the metadata of the envelope, if any.
+func (e *envelope) Meta() json.RawMessage {
+	if e == nil {
+		return nil
+	}
+	return e.meta
+}
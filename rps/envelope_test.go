@@ -0,0 +1,72 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithResponseEnvelope(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		responseBody   string
+		expectedOutput *Resume
+		expectedMeta   json.RawMessage
+		expectedError  error
+	}{
+		{
+			name:           "flat response by default",
+			responseBody:   `{"first_name":"Morgana"}`,
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:           "enveloped response",
+			options:        []Option{WithResponseEnvelope("data")},
+			responseBody:   `{"data":{"first_name":"Morgana"},"meta":{"version":"2"}}`,
+			expectedOutput: &Resume{FirstName: "Morgana"},
+			expectedMeta:   json.RawMessage(`{"version":"2"}`),
+		},
+		{
+			name:           "enveloped response without meta",
+			options:        []Option{WithResponseEnvelope("result")},
+			responseBody:   `{"result":{"first_name":"Morgana"}}`,
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:          "missing envelope key",
+			options:       []Option{WithResponseEnvelope("data")},
+			responseBody:  `{"first_name":"Morgana"}`,
+			expectedError: errors.New(`performing request: decoding response: missing envelope key "data"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.responseBody))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			var md CallMetadata
+			output, err := client.ParseDocument(context.TODO(), []byte{}, WithCallMetadata(&md))
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, output)
+				require.Equal(t, tc.expectedMeta, md.Meta)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package rps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ETagEntry is a response of the service cached along with its ETag.
+type ETagEntry struct {
+	ETag        string
+	ContentType string
+	Body        []byte
+}
+
+// ETagCache stores the responses of the service. Each key is the SHA-256
+// hash of a parsed document and of how it was requested. Callers may keep
+// the entries anywhere, e.g. in a database, so that unchanged documents
+// are not parsed again across runs. Implementations must be safe for
+// concurrent use.
+type ETagCache interface {
+	// Get returns the entry stored under the key, if any.
+	Get(key string) (ETagEntry, bool)
+
+	// Set stores the entry under the key.
+	Set(key string, entry ETagEntry)
+}
+
+// memoryETagCache is an ETagCache holding the entries in memory.
+type memoryETagCache struct {
+	mu      sync.Mutex
+	entries map[string]ETagEntry
+}
+
+// NewMemoryETagCache returns an ETagCache holding the
+// entries in memory, for the lifetime of the process.
+func NewMemoryETagCache() ETagCache {
+	return &memoryETagCache{entries: make(map[string]ETagEntry)}
+}
+
+// Get returns the entry stored under the key, if any.
+func (c *memoryETagCache) Get(key string) (ETagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// Set stores the entry under the key.
+func (c *memoryETagCache) Set(key string, entry ETagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// etagCacheVariant holds what, besides the document, makes the service
+// respond differently, so that each variant is cached apart.
+type etagCacheVariant struct {
+	url          string
+	accept       string
+	modelVersion int
+	ocr          *bool
+}
+
+// etagCacheVariant returns the variant of the request, sent for a
+// document that is likely scanned or not.
+func (r *resumeParsingServiceClient) etagCacheVariant(req *http.Request, likelyScanned bool) etagCacheVariant {
+	return etagCacheVariant{
+		url:          req.URL.String(),
+		accept:       req.Header.Get("Accept"),
+		modelVersion: r.modelVersion,
+		ocr:          r.ocrFlag(likelyScanned),
+	}
+}
+
+// etagCacheKey returns the key of the document
+// requested with the variant in the ETag cache.
+func etagCacheKey(fileContents []byte, variant etagCacheVariant) string {
+	var ocr string
+	if variant.ocr != nil {
+		ocr = strconv.FormatBool(*variant.ocr)
+	}
+	h := sha256.New()
+	_, _ = h.Write(fileContents)
+	_, _ = fmt.Fprintf(h, "\x00%s\x00%s\x00%d\x00%s", variant.url, variant.accept, variant.modelVersion, ocr)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedResponse returns the key of the document in the ETag cache, if
+// any, and its cached response, if any, setting the If-None-Match header
+// of the request to the ETag of the cached response.
+func (r *resumeParsingServiceClient) cachedResponse(req *http.Request, fileContents []byte, likelyScanned bool) (string, *ETagEntry) {
+	if r.etagCache == nil {
+		return "", nil
+	}
+	key := etagCacheKey(fileContents, r.etagCacheVariant(req, likelyScanned))
+	entry, ok := r.etagCache.Get(key)
+	if !ok || entry.ETag == "" {
+		return key, nil
+	}
+	req.Header.Set("If-None-Match", entry.ETag)
+	return key, &entry
+}
+
+// cacheResponse stores the response to the document in the ETag
+// cache, if any, provided it has a key, an ETag and a body.
+func (r *resumeParsingServiceClient) cacheResponse(key string, resp *http.Response, raw []byte) {
+	if r.etagCache == nil || key == "" || resp.StatusCode == http.StatusNotModified {
+		return
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" || len(raw) == 0 {
+		return
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType(contentType) == eventStreamContentType {
+		// The complete event of a stream is cached, which is JSON.
+		contentType = jsonContentType
+	}
+	r.etagCache.Set(key, ETagEntry{
+		ETag:        etag,
+		ContentType: contentType,
+		Body:        raw,
+	})
+}
@@ -0,0 +1,175 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithETagCache(t *testing.T) {
+	testCases := []struct {
+		name                string
+		etag                string
+		cached              *ETagEntry
+		expectedIfNoneMatch string
+		expectedStatusCode  int
+		expectedFirstName   string
+		expectedEntry       *ETagEntry
+	}{
+		{
+			name:               "cache miss stores the response",
+			etag:               `"v1"`,
+			expectedStatusCode: http.StatusOK,
+			expectedFirstName:  "Morgana",
+			expectedEntry: &ETagEntry{
+				ETag:        `"v1"`,
+				ContentType: "application/json",
+				Body:        []byte(`{"first_name":"Morgana"}`),
+			},
+		},
+		{
+			name:               "response without etag is not stored",
+			expectedStatusCode: http.StatusOK,
+			expectedFirstName:  "Morgana",
+		},
+		{
+			name: "not modified returns the cached resume",
+			etag: `"v1"`,
+			cached: &ETagEntry{
+				ETag:        `"v1"`,
+				ContentType: "application/json",
+				Body:        []byte(`{"first_name":"Cached"}`),
+			},
+			expectedIfNoneMatch: `"v1"`,
+			expectedStatusCode:  http.StatusNotModified,
+			expectedFirstName:   "Cached",
+			expectedEntry: &ETagEntry{
+				ETag:        `"v1"`,
+				ContentType: "application/json",
+				Body:        []byte(`{"first_name":"Cached"}`),
+			},
+		},
+		{
+			name: "modified response replaces the cached one",
+			etag: `"v2"`,
+			cached: &ETagEntry{
+				ETag:        `"v1"`,
+				ContentType: "application/json",
+				Body:        []byte(`{"first_name":"Cached"}`),
+			},
+			expectedIfNoneMatch: `"v1"`,
+			expectedStatusCode:  http.StatusOK,
+			expectedFirstName:   "Morgana",
+			expectedEntry: &ETagEntry{
+				ETag:        `"v2"`,
+				ContentType: "application/json",
+				Body:        []byte(`{"first_name":"Morgana"}`),
+			},
+		},
+	}
+	doc := []byte("resume")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ifNoneMatch string
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ifNoneMatch = r.Header.Get("If-None-Match")
+				if tc.etag != "" {
+					w.Header().Set("ETag", tc.etag)
+				}
+				if ifNoneMatch != "" && ifNoneMatch == tc.etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			cache := NewMemoryETagCache()
+			key := etagCacheKey(doc, etagCacheVariant{url: svr.URL + "/api/parse", modelVersion: ModelV1})
+			if tc.cached != nil {
+				cache.Set(key, *tc.cached)
+			}
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithETagCache(cache))
+			var md CallMetadata
+			resume, err := client.ParseDocument(context.TODO(), doc, WithCallMetadata(&md))
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedIfNoneMatch, ifNoneMatch)
+			require.Equal(t, tc.expectedStatusCode, md.StatusCode)
+			require.Equal(t, tc.expectedFirstName, resume.FirstName)
+			entry, ok := cache.Get(key)
+			if tc.expectedEntry == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.expectedEntry.ETag, entry.ETag)
+			require.Equal(t, tc.expectedEntry.ContentType, entry.ContentType)
+			require.JSONEq(t, string(tc.expectedEntry.Body), string(entry.Body))
+		})
+	}
+}
+
+func TestParseDocumentWithETagCacheVariants(t *testing.T) {
+	testCases := []struct {
+		name                string
+		options             []Option
+		callOptions         func(baseUrl string) []CallOption
+		expectedIfNoneMatch string
+	}{
+		{
+			name:                "same request is revalidated",
+			expectedIfNoneMatch: `"v1"`,
+		},
+		{
+			name: "other base url is not revalidated",
+			callOptions: func(baseUrl string) []CallOption {
+				return []CallOption{WithBaseUrlOverride(baseUrl + "/canary")}
+			},
+		},
+		{
+			name:    "other accept header is not revalidated",
+			options: []Option{WithAcceptEncoding("application/json; charset=utf-8")},
+		},
+		{
+			name:    "other model version is not revalidated",
+			options: []Option{WithModelVersion(ModelV2)},
+		},
+		{
+			name:    "other ocr flag is not revalidated",
+			options: []Option{WithOCR(true)},
+		},
+	}
+	doc := []byte("resume")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ifNoneMatch string
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ifNoneMatch = r.Header.Get("If-None-Match")
+				w.Header().Set("ETag", `"v1"`)
+				if ifNoneMatch == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			cache := NewMemoryETagCache()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithETagCache(cache))
+			_, err := client.ParseDocument(context.TODO(), doc)
+			require.Nil(t, err)
+
+			client = NewResumeParsingServiceClient("TOKEN", svr.URL, append(tc.options, WithETagCache(cache))...)
+			var callOptions []CallOption
+			if tc.callOptions != nil {
+				callOptions = tc.callOptions(svr.URL)
+			}
+			_, err = client.ParseDocument(context.TODO(), doc, callOptions...)
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedIfNoneMatch, ifNoneMatch)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package rps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// canonicalOrder returns a sorted copy of the elements, ordered by their
+// JSON encoding, so that their order does not affect the fingerprint.
+// Empty slices are returned as nil, like missing ones.
+func canonicalOrder[T any](elements []T) []T {
+	if len(elements) == 0 {
+		return nil
+	}
+	type encodedElement struct {
+		element T
+		encoded string
+	}
+	encodedElements := make([]encodedElement, 0, len(elements))
+	for _, element := range elements {
+		b, _ := json.Marshal(element)
+		encodedElements = append(encodedElements, encodedElement{element: element, encoded: string(b)})
+	}
+	sort.SliceStable(encodedElements, func(i, j int) bool {
+		return encodedElements[i].encoded < encodedElements[j].encoded
+	})
+	sorted := make([]T, 0, len(elements))
+	for _, e := range encodedElements {
+		sorted = append(sorted, e.element)
+	}
+	return sorted
+}
+
+// canonicalResume returns a copy of the resume holding only its
+// semantically meaningful fields, with its lists in canonical order.
+// Volatile fields, i.e. the PDF location, the confidence scores and
+// the extensions, are left out, and dates are in UTC.
+func canonicalResume(r *Resume) Resume {
+	canonical := *r
+	canonical.Pdf = ""
+	canonical.FieldConfidence = nil
+	canonical.Extensions = nil
+	canonical.Emails = canonicalOrder(r.Emails)
+	canonical.Languages = canonicalOrder(r.Languages)
+	canonical.SocialUrls = canonicalOrder(r.SocialUrls)
+	canonical.PhoneNumbers = canonicalOrder(r.PhoneNumbers)
+	canonical.Positions = nil
+	for _, position := range r.Positions {
+		position.Confidence = 0
+		position.StartDate = inLocation(position.StartDate, time.UTC)
+		position.EndDate = inLocation(position.EndDate, time.UTC)
+		canonical.Positions = append(canonical.Positions, position)
+	}
+	canonical.Positions = canonicalOrder(canonical.Positions)
+	canonical.Educations = nil
+	for _, education := range r.Educations {
+		education.Confidence = 0
+		education.StartDate = inLocation(education.StartDate, time.UTC)
+		education.EndDate = inLocation(education.EndDate, time.UTC)
+		canonical.Educations = append(canonical.Educations, education)
+	}
+	canonical.Educations = canonicalOrder(canonical.Educations)
+	canonical.Skills = nil
+	for _, skill := range r.Skills {
+		skill.Aliases = canonicalOrder(skill.Aliases)
+		canonical.Skills = append(canonical.Skills, skill)
+	}
+	canonical.Skills = canonicalOrder(canonical.Skills)
+	return canonical
+}
+
+// Fingerprint returns a stable hash of the semantically meaningful
+// fields of the resume, so that callers can detect whether a re-parse
+// changed anything meaningful. It is the hex-encoded SHA-256 digest of
+// a canonical serialization, where the order of the lists does not
+// matter and volatile fields, i.e. the PDF location, the confidence
+// scores and the extensions, are left out.
+func (r *Resume) Fingerprint() string {
+	b, _ := json.Marshal(canonicalResume(r))
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
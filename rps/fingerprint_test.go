@@ -0,0 +1,111 @@
+package rps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fingerprintedResume returns a resume with lists of several elements.
+func fingerprintedResume() *Resume {
+	return &Resume{
+		FirstName: "Morgana",
+		LastName:  "Favero",
+		Pdf:       "https://storage.example.com/resumes/1.pdf",
+		Emails:    []string{"favero.morgana@gmail.com", "morgana@upenn.edu"},
+		Positions: []Position{
+			{Title: "Postdoctoral Researcher", Organization: "UPenn", StartDate: date(2019, 1), Confidence: 0.9},
+			{Title: "Research Assistant", Organization: "USP", StartDate: date(2015, 3), EndDate: date(2018, 12), Confidence: 0.8},
+		},
+		Educations: []Education{
+			{Degree: "PhD", Organization: "USP", Confidence: 0.95},
+			{Degree: "MD", Organization: "USP", Confidence: 0.7},
+		},
+		Skills: []Skill{
+			{Name: "Electrophysiology", NumMonths: 31},
+			{Name: "Go", NumMonths: 24, Aliases: []string{"Golang", "Go lang"}},
+		},
+		FieldConfidence: Confidences{"first_name": 0.99},
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	testCases := []struct {
+		name          string
+		modify        func(resume *Resume)
+		expectedEqual bool
+	}{
+		{
+			name:          "identical content",
+			modify:        func(resume *Resume) {},
+			expectedEqual: true,
+		},
+		{
+			name: "reordered lists",
+			modify: func(resume *Resume) {
+				resume.Emails[0], resume.Emails[1] = resume.Emails[1], resume.Emails[0]
+				resume.Positions[0], resume.Positions[1] = resume.Positions[1], resume.Positions[0]
+				resume.Educations[0], resume.Educations[1] = resume.Educations[1], resume.Educations[0]
+				resume.Skills[0], resume.Skills[1] = resume.Skills[1], resume.Skills[0]
+				resume.Skills[0].Aliases = []string{"Go lang", "Golang"}
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "volatile fields",
+			modify: func(resume *Resume) {
+				resume.Pdf = "https://storage.example.com/resumes/2.pdf"
+				resume.Positions[0].Confidence = 0.5
+				resume.Educations[1].Confidence = 0.5
+				resume.FieldConfidence = nil
+				resume.Extensions = map[string]any{"headline": "Researcher"}
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "dates in another zone",
+			modify: func(resume *Resume) {
+				dateTimezoneNormalizer(time.FixedZone("EST", -5*60*60))(resume)
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "empty and missing lists",
+			modify: func(resume *Resume) {
+				resume.Languages = []string{}
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "changed field",
+			modify: func(resume *Resume) {
+				resume.Positions[1].EndDate = date(2019, 1)
+			},
+		},
+		{
+			name: "added element",
+			modify: func(resume *Resume) {
+				resume.Skills = append(resume.Skills, Skill{Name: "Python"})
+			},
+		},
+	}
+	expected := fingerprintedResume().Fingerprint()
+	require.Len(t, expected, 64)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resume := fingerprintedResume()
+			tc.modify(resume)
+			require.Equal(t, tc.expectedEqual, resume.Fingerprint() == expected)
+		})
+	}
+}
+
+func TestFingerprintDoesNotModifyResume(t *testing.T) {
+	resume := fingerprintedResume()
+	resume.Emails[0], resume.Emails[1] = resume.Emails[1], resume.Emails[0]
+	_ = resume.Fingerprint()
+	require.Equal(t, []string{"morgana@upenn.edu", "favero.morgana@gmail.com"}, resume.Emails)
+	require.Equal(t, "https://storage.example.com/resumes/1.pdf", resume.Pdf)
+	require.Equal(t, 0.9, resume.Positions[0].Confidence)
+}
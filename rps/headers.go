@@ -0,0 +1,29 @@
+package rps
+
+import (
+	"net/http"
+	"strings"
+)
+
+// tokenHeader is the header carrying the token of the service.
+const tokenHeader = "token"
+
+// validHeaders returns the custom headers without the token header,
+// which could otherwise silently replace the token of the client.
+func (r *resumeParsingServiceClient) validHeaders(headers map[string]string) map[string]string {
+	for key := range headers {
+		if strings.EqualFold(key, tokenHeader) {
+			logPrintf("%s: ignoring custom header %q", r.logPrefix(), key)
+			delete(headers, key)
+		}
+	}
+	return headers
+}
+
+// setCustomHeaders sets the headers given through WithHeader and
+// WithHeaders.
+func (r *resumeParsingServiceClient) setCustomHeaders(req *http.Request) {
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+}
@@ -0,0 +1,71 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithHeaders(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		expectedHeaders  map[string]string
+		expectedWarnings []string
+	}{
+		{
+			name: "no custom headers by default",
+			expectedHeaders: map[string]string{
+				"X-Correlation-Id": "",
+				"X-Tenant-Id":      "",
+			},
+		},
+		{
+			name: "custom headers",
+			options: []Option{
+				WithHeader("X-Correlation-Id", "c0ffee"),
+				WithHeaders(map[string]string{"x-tenant-id": "camelot"}),
+			},
+			expectedHeaders: map[string]string{
+				"X-Correlation-Id": "c0ffee",
+				"X-Tenant-Id":      "camelot",
+			},
+		},
+		{
+			name:             "token header is ignored",
+			options:          []Option{WithHeaders(map[string]string{"Token": "STOLEN", "X-Tenant-Id": "camelot"})},
+			expectedHeaders:  map[string]string{"X-Tenant-Id": "camelot"},
+			expectedWarnings: []string{`rps: ignoring custom header "Token"`},
+		},
+	}
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []string
+			logPrintf = func(format string, v ...any) {
+				warnings = append(warnings, fmt.Sprintf(format, v...))
+			}
+			var header http.Header
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, "TOKEN", header.Get("token"))
+			require.Equal(t, jsonContentType, header.Get("Content-Type"))
+			for key, value := range tc.expectedHeaders {
+				require.Equal(t, value, header.Get(key))
+			}
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
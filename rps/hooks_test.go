@@ -0,0 +1,158 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithResponseHook(t *testing.T) {
+	testCases := []struct {
+		name                string
+		statusCode          int
+		expectedInvocations int
+	}{
+		{
+			name:                "successful parse",
+			statusCode:          http.StatusOK,
+			expectedInvocations: 1,
+		},
+		{
+			name:       "failed parse",
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	const body = `{"first_name":"Morgana","last_name":"Favero","emails":["Favero.Morgana@gmail.com"]}`
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(body))
+			}))
+			defer svr.Close()
+			var invocations int
+			var hookResume *Resume
+			var hookRaw []byte
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithEmailNormalization(),
+				WithResponseHook(func(resume *Resume, raw []byte) {
+					invocations++
+					hookResume = resume
+					hookRaw = raw
+				}),
+			)
+			resume, _ := client.ParseDocument(context.TODO(), []byte{})
+			require.Equal(t, tc.expectedInvocations, invocations)
+			if tc.expectedInvocations == 0 {
+				return
+			}
+			require.Equal(t, resume, hookResume)
+			require.JSONEq(t, body, string(hookRaw))
+			var rawResume Resume
+			require.Nil(t, json.Unmarshal(hookRaw, &rawResume))
+			require.Equal(t, "Favero.Morgana@gmail.com", rawResume.Emails[0])
+			require.Equal(t, "favero.morgana@gmail.com", hookResume.Emails[0])
+			require.Equal(t, rawResume.FirstName, hookResume.FirstName)
+		})
+	}
+}
+
+func TestParseDocumentWithResponseHookDecodesOnce(t *testing.T) {
+	const body = `{"first_name":"Morgana", "last_name":"Favero"}`
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer svr.Close()
+	var decodings int
+	var hookRaw []byte
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithDecoder("application/json", DecoderFunc(func(r io.Reader, v any) error {
+			decodings++
+			return json.NewDecoder(r).Decode(v)
+		})),
+		WithResponseHook(func(resume *Resume, raw []byte) {
+			hookRaw = raw
+		}),
+	)
+	resume, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.Equal(t, "Favero", resume.LastName)
+	require.Equal(t, 1, decodings)
+	// The raw body is the one received, not a re-encoding of it.
+	require.Equal(t, body, string(hookRaw))
+}
+
+func TestParseDocumentWithUnmarshalInterceptor(t *testing.T) {
+	testCases := []struct {
+		name               string
+		interceptor        func(raw []byte, resume *Resume) error
+		expectedExtensions map[string]any
+		expectedError      error
+	}{
+		{
+			name: "no interceptor",
+		},
+		{
+			name: "enriches a field from an extra raw key",
+			interceptor: func(raw []byte, resume *Resume) error {
+				var extra struct {
+					Headline string `json:"headline"`
+				}
+				if err := json.Unmarshal(raw, &extra); err != nil {
+					return err
+				}
+				resume.Extensions = map[string]any{"headline": extra.Headline}
+				return nil
+			},
+			expectedExtensions: map[string]any{"headline": "Backend engineer"},
+		},
+		{
+			name: "error",
+			interceptor: func(raw []byte, resume *Resume) error {
+				return errors.New("missing headline")
+			},
+			expectedError: errors.New("intercepting unmarshal: missing headline"),
+		},
+	}
+	const body = `{"first_name":"Morgana","emails":["Favero.Morgana@gmail.com"],"headline":"Backend engineer"}`
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}))
+			defer svr.Close()
+			var interceptedEmail string
+			options := []Option{WithEmailNormalization()}
+			if tc.interceptor != nil {
+				options = append(options, WithUnmarshalInterceptor(func(raw []byte, resume *Resume) error {
+					interceptedEmail = resume.Emails[0]
+					return tc.interceptor(raw, resume)
+				}))
+			}
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			require.Nil(t, tc.expectedError)
+			require.Equal(t, "Morgana", resume.FirstName)
+			require.Equal(t, tc.expectedExtensions, resume.Extensions)
+			if tc.interceptor != nil {
+				require.Equal(t, "Favero.Morgana@gmail.com", interceptedEmail)
+			}
+		})
+	}
+}
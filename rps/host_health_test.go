@@ -0,0 +1,92 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithHostHealthTracking(t *testing.T) {
+	testCases := []struct {
+		name                string
+		options             func(fallbackBaseUrl string) []Option
+		expectedErrors      int
+		expectedFailingHits int32
+		expectedHealthyHits int32
+	}{
+		{
+			name:                "without tracking",
+			expectedErrors:      3,
+			expectedFailingHits: 6,
+		},
+		{
+			name: "traffic shifts to the healthy host",
+			options: func(fallbackBaseUrl string) []Option {
+				return []Option{WithHostHealthTracking(fallbackBaseUrl), WithHostFailureCooldown(1, time.Minute)}
+			},
+			expectedFailingHits: 1,
+			expectedHealthyHits: 3,
+		},
+		{
+			name: "traffic shifts after the default number of failures",
+			options: func(fallbackBaseUrl string) []Option {
+				return []Option{WithHostHealthTracking(fallbackBaseUrl)}
+			},
+			expectedErrors:      1,
+			expectedFailingHits: 3,
+			expectedHealthyHits: 2,
+		},
+		{
+			name: "no host to shift to",
+			options: func(fallbackBaseUrl string) []Option {
+				return []Option{WithHostHealthTracking()}
+			},
+			expectedErrors:      3,
+			expectedFailingHits: 6,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var failingHits, healthyHits atomic.Int32
+			failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				failingHits.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer failing.Close()
+			healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				healthyHits.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer healthy.Close()
+			options := []Option{
+				WithMaxRetries(1),
+				WithRetryWaitMin(time.Millisecond),
+				WithRetryWaitMax(time.Millisecond),
+				WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil),
+			}
+			if tc.options != nil {
+				// The healthy host is the fallback of the failing one.
+				options = append(options, tc.options(healthy.URL)...)
+			}
+			client := NewResumeParsingServiceClient("TOKEN", failing.URL, options...)
+			errs := 0
+			for i := 0; i < 3; i++ {
+				resume, err := client.ParseDocument(context.TODO(), []byte("file"))
+				if err != nil {
+					errs++
+					continue
+				}
+				require.Equal(t, "Morgana", resume.FirstName)
+			}
+			require.Equal(t, tc.expectedErrors, errs)
+			require.Equal(t, tc.expectedFailingHits, failingHits.Load())
+			require.Equal(t, tc.expectedHealthyHits, healthyHits.Load())
+		})
+	}
+}
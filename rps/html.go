@@ -0,0 +1,65 @@
+package rps
+
+import (
+	"html"
+	"strings"
+)
+
+// lineBreakTags are the HTML tags replaced by a line
+// break when stripped, to keep paragraphs apart.
+var lineBreakTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "ul": true, "ol": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// isTagStart checks whether the character following a '<'
+// starts an HTML tag, comment or declaration. This keeps
+// angle brackets used in prose, such as "a < b", untouched.
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagName returns the lowercased name of the given tag contents,
+// e.g. "br" for "<br/>" and "p" for "</p>".
+func tagName(tag string) string {
+	tag = strings.TrimPrefix(tag, "/")
+	end := strings.IndexAny(tag, " \t\n/")
+	if end >= 0 {
+		tag = tag[:end]
+	}
+	return strings.ToLower(tag)
+}
+
+// stripHTML removes HTML tags from s, unescaping HTML entities.
+// A '<' only starts a tag when followed by a letter, '/' or '!'
+// and closed by a '>'; otherwise it is kept as text.
+func stripHTML(s string) string {
+	if !strings.Contains(s, "<") && !strings.Contains(s, "&") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		end := -1
+		if s[i] == '<' && i+1 < len(s) && isTagStart(s[i+1]) {
+			end = strings.IndexByte(s[i:], '>')
+		}
+		if end < 0 {
+			b.WriteByte(s[i])
+			continue
+		}
+		if lineBreakTags[tagName(s[i+1:i+end])] {
+			b.WriteByte('\n')
+		}
+		i += end
+	}
+	return strings.TrimSpace(html.UnescapeString(b.String()))
+}
+
+// stripResumeHTML removes HTML tags from the resume
+// summary and position descriptions.
+func stripResumeHTML(resume *Resume) {
+	resume.Summary = stripHTML(resume.Summary)
+	for i := range resume.Positions {
+		resume.Positions[i].Description = stripHTML(resume.Positions[i].Description)
+	}
+}
@@ -0,0 +1,92 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripHTML(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "plain text",
+			input:          "Led a team of 5 researchers",
+			expectedOutput: "Led a team of 5 researchers",
+		},
+		{
+			name:           "inline tags",
+			input:          "Led a <b>team</b> of <a href=\"x\">5</a> researchers",
+			expectedOutput: "Led a team of 5 researchers",
+		},
+		{
+			name:           "block tags",
+			input:          "<p>Research</p><p>Teaching<br/>Writing</p>",
+			expectedOutput: "Research\n\nTeaching\nWriting",
+		},
+		{
+			name:           "entities",
+			input:          "<li>R&amp;D &lt;lab&gt;</li>",
+			expectedOutput: "R&D <lab>",
+		},
+		{
+			name:           "angle brackets in prose",
+			input:          "reduced latency to < 5ms and kept 3 > 2",
+			expectedOutput: "reduced latency to < 5ms and kept 3 > 2",
+		},
+		{
+			name:           "unclosed tag",
+			input:          "budget <increased by 10%",
+			expectedOutput: "budget <increased by 10%",
+		},
+		{
+			name:           "comments",
+			input:          "Research<!-- hidden -->",
+			expectedOutput: "Research",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, stripHTML(tc.input))
+		})
+	}
+}
+
+func TestParseDocumentWithStripHTML(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedOutput *Resume
+	}{
+		{
+			name: "html is kept by default",
+			expectedOutput: &Resume{
+				Summary:   "<p>I am a <b>Neuroscientist</b></p>",
+				Positions: []Position{{Description: "<ul><li>Research</li></ul>"}},
+			},
+		},
+		{
+			name:    "with strip html",
+			options: []Option{WithStripHTML()},
+			expectedOutput: &Resume{
+				Summary:   "I am a Neuroscientist",
+				Positions: []Position{{Description: "Research"}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				Summary:   "<p>I am a <b>Neuroscientist</b></p>",
+				Positions: []Position{{Description: "<ul><li>Research</li></ul>"}},
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package rps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// identityName returns the normalized full name, ignoring the
+// middle name, which is often missing from some documents.
+func (r *Resume) identityName() string {
+	return strings.Join(strings.Fields(strings.ToLower(r.FirstName+" "+r.LastName)), " ")
+}
+
+// identityContact returns the normalized primary email or,
+// without any email, the digits of the primary phone number.
+func (r *Resume) identityContact() string {
+	for _, email := range r.Emails {
+		if email = normalizeEmail(email, true); email != "" {
+			return "email:" + email
+		}
+	}
+	for _, phone := range r.PhoneNumbers {
+		if number := digits(phone.NationalNumber); number != "" {
+			return "phone:" + number
+		}
+	}
+	return ""
+}
+
+// IdentityKey returns a stable key identifying the candidate, computed
+// from the normalized name and primary email, or primary phone number
+// when there is no email. Resumes of the same candidate parsed from
+// different documents produce the same key, so that batch pipelines can
+// dedupe them. It returns an empty string when the resume has neither
+// an email nor a phone number.
+func (r *Resume) IdentityKey() string {
+	contact := r.identityContact()
+	if contact == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(r.identityName() + "\n" + contact))
+	return hex.EncodeToString(sum[:])
+}
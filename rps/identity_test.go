@@ -0,0 +1,101 @@
+package rps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityKey(t *testing.T) {
+	testCases := []struct {
+		name          string
+		resume        *Resume
+		other         *Resume
+		expectedEqual bool
+	}{
+		{
+			name: "same candidate from different documents",
+			resume: &Resume{
+				FirstName: "Morgana",
+				LastName:  "Le Fay",
+				Emails:    []string{"Morgana.LeFay@gmail.com", "morgana@avalon.org"},
+			},
+			other: &Resume{
+				FirstName:  " morgana ",
+				MiddleName: "Pendragon",
+				LastName:   "LE  FAY",
+				Emails:     []string{"morganalefay+jobs@gmail.com"},
+				Summary:    "Sorceress",
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "fallback to phone number",
+			resume: &Resume{
+				FirstName:    "Morgana",
+				LastName:     "Le Fay",
+				PhoneNumbers: []PhoneNumber{{CountryCode: "1", NationalNumber: "(267) 721-0053"}},
+			},
+			other: &Resume{
+				FirstName:    "Morgana",
+				LastName:     "Le Fay",
+				PhoneNumbers: []PhoneNumber{{NationalNumber: "267.721.0053"}},
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "different emails",
+			resume: &Resume{
+				FirstName: "Morgana",
+				LastName:  "Le Fay",
+				Emails:    []string{"morgana@avalon.org"},
+			},
+			other: &Resume{
+				FirstName: "Morgana",
+				LastName:  "Le Fay",
+				Emails:    []string{"morgana@camelot.org"},
+			},
+		},
+		{
+			name: "different names",
+			resume: &Resume{
+				FirstName: "Morgana",
+				LastName:  "Le Fay",
+				Emails:    []string{"morgana@avalon.org"},
+			},
+			other: &Resume{
+				FirstName: "Vivian",
+				LastName:  "Le Fay",
+				Emails:    []string{"morgana@avalon.org"},
+			},
+		},
+		{
+			name: "email takes precedence over phone number",
+			resume: &Resume{
+				FirstName:    "Morgana",
+				Emails:       []string{"morgana@avalon.org"},
+				PhoneNumbers: []PhoneNumber{{NationalNumber: "2677210053"}},
+			},
+			other: &Resume{
+				FirstName:    "Morgana",
+				PhoneNumbers: []PhoneNumber{{NationalNumber: "2677210053"}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := tc.resume.IdentityKey()
+			require.Len(t, key, 64)
+			if tc.expectedEqual {
+				require.Equal(t, key, tc.other.IdentityKey())
+			} else {
+				require.NotEqual(t, key, tc.other.IdentityKey())
+			}
+		})
+	}
+}
+
+func TestIdentityKeyWithoutContact(t *testing.T) {
+	resume := &Resume{FirstName: "Morgana", Emails: []string{" "}}
+	require.Empty(t, resume.IdentityKey())
+}
@@ -0,0 +1,58 @@
+package rps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLanguageNotAccepted is returned, wrapped in a
+// *LanguageNotAcceptedError, when the detected language of the resume
+// is not one of those accepted through WithAcceptedDetectedLanguages.
+var ErrLanguageNotAccepted = errors.New("detected language not accepted")
+
+// LanguageNotAcceptedError is returned when the detected language of
+// the resume is not accepted. errors.Is matches ErrLanguageNotAccepted.
+type LanguageNotAcceptedError struct {
+	// Language is the detected language, empty if none was detected.
+	Language string
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *LanguageNotAcceptedError) Error() string {
+	return fmt.Sprintf("%v: %q", ErrLanguageNotAccepted, e.Language)
+}
+
+// Unwrap returns ErrLanguageNotAccepted.
+func (e *LanguageNotAcceptedError) Unwrap() error {
+	return ErrLanguageNotAccepted
+}
+
+// languageCode normalizes a language code for comparison.
+func languageCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// languageCodes returns the set of the normalized language codes.
+func languageCodes(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[languageCode(code)] = true
+	}
+	return set
+}
+
+// checkDetectedLanguage returns a *LanguageNotAcceptedError if the
+// detected language of the resume is not accepted, ignoring case.
+// Every language is accepted unless WithAcceptedDetectedLanguages is set
+// with at least one code.
+func (r *resumeParsingServiceClient) checkDetectedLanguage(resume *Resume) error {
+	if len(r.acceptedLanguages) == 0 {
+		return nil
+	}
+	if r.acceptedLanguages[languageCode(resume.DetectedLanguage)] {
+		return nil
+	}
+	return &LanguageNotAcceptedError{Language: resume.DetectedLanguage}
+}
@@ -0,0 +1,69 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithAcceptedDetectedLanguages(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		detectedLanguage string
+		expectedError    error
+	}{
+		{
+			name:             "every language is accepted by default",
+			detectedLanguage: "fr",
+		},
+		{
+			name:             "every language is accepted without codes",
+			options:          []Option{WithAcceptedDetectedLanguages()},
+			detectedLanguage: "fr",
+		},
+		{
+			name:             "accepted language",
+			options:          []Option{WithAcceptedDetectedLanguages("en", "fr")},
+			detectedLanguage: "fr",
+		},
+		{
+			name:             "language is case-insensitive",
+			options:          []Option{WithAcceptedDetectedLanguages("EN")},
+			detectedLanguage: "en",
+		},
+		{
+			name:             "rejected language",
+			options:          []Option{WithAcceptedDetectedLanguages("en", "fr")},
+			detectedLanguage: "de",
+			expectedError:    errors.New(`detected language not accepted: "de"`),
+		},
+		{
+			name:          "missing language is rejected",
+			options:       []Option{WithAcceptedDetectedLanguages("en")},
+			expectedError: errors.New(`detected language not accepted: ""`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{FirstName: "Morgana", DetectedLanguage: tc.detectedLanguage}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				require.ErrorIs(t, err, ErrLanguageNotAccepted)
+				var langErr *LanguageNotAcceptedError
+				require.ErrorAs(t, err, &langErr)
+				require.Equal(t, tc.detectedLanguage, langErr.Language)
+				require.Nil(t, resume)
+				return
+			}
+			require.Nil(t, tc.expectedError)
+			require.Equal(t, tc.detectedLanguage, resume.DetectedLanguage)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithLatencyHistogram(t *testing.T) {
+	testCases := []struct {
+		name               string
+		options            []Option
+		statusCodes        []int
+		closeServer        bool
+		expectedStatus     int
+		expectedClientName string
+		expectedError      bool
+	}{
+		{
+			name:           "success after a retry",
+			statusCodes:    []int{http.StatusInternalServerError, http.StatusOK},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "final failure",
+			statusCodes:    []int{http.StatusBadRequest},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:          "retries exhausted",
+			statusCodes:   []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedError: true,
+		},
+		{
+			name:          "no response",
+			closeServer:   true,
+			expectedError: true,
+		},
+		{
+			name:               "client name",
+			options:            []Option{WithClientName("premium")},
+			statusCodes:        []int{http.StatusOK},
+			expectedStatus:     http.StatusOK,
+			expectedClientName: "premium",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCodes[attempt-1])
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer svr.Close()
+			if tc.closeServer {
+				svr.Close()
+			}
+			var invocations []int
+			var durations []time.Duration
+			var clientNames []string
+			options := append([]Option{
+				WithMaxRetries(1),
+				WithRetryableStatusCodes([]int{http.StatusInternalServerError}, nil),
+				WithLatencyHistogram(func(d time.Duration, status int, clientName string) {
+					invocations = append(invocations, status)
+					durations = append(durations, d)
+					clientNames = append(clientNames, clientName)
+				}),
+			}, tc.options...)
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Equal(t, tc.expectedError, err != nil)
+			require.Equal(t, []int{tc.expectedStatus}, invocations)
+			require.Greater(t, durations[0], time.Duration(0))
+			require.Equal(t, []string{tc.expectedClientName}, clientNames)
+		})
+	}
+}
+
+func TestParseDocumentWithSlowParseThreshold(t *testing.T) {
+	testCases := []struct {
+		name          string
+		delay         time.Duration
+		threshold     time.Duration
+		expectedCalls int
+	}{
+		{
+			name:          "slow parse",
+			delay:         50 * time.Millisecond,
+			threshold:     10 * time.Millisecond,
+			expectedCalls: 1,
+		},
+		{
+			name:          "fast parse",
+			threshold:     time.Minute,
+			expectedCalls: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.delay)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer svr.Close()
+			var durations []time.Duration
+			var clientNames []string
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithClientName("premium"),
+				WithSlowParseThreshold(tc.threshold, func(d time.Duration, clientName string) {
+					durations = append(durations, d)
+					clientNames = append(clientNames, clientName)
+				}),
+			)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Len(t, durations, tc.expectedCalls)
+			for i, d := range durations {
+				require.GreaterOrEqual(t, d, tc.delay)
+				require.Equal(t, "premium", clientNames[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package rps
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// payloadTooLargeError is a document too large, either
+// rejected by the service or caught before being sent.
+type payloadTooLargeError struct {
+	message string
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *payloadTooLargeError) Error() string {
+	return e.message
+}
+
+// Is reports that every payload too large error matches ErrPayloadTooLarge.
+func (e *payloadTooLargeError) Is(target error) bool {
+	return target == ErrPayloadTooLarge
+}
+
+var (
+	// ErrPayloadTooLarge is returned along with the underlying error when
+	// the service rejected the document as too large (413), so that callers
+	// can downsample or compress it before trying again. ErrBodyTooLarge
+	// matches it too.
+	ErrPayloadTooLarge error = &payloadTooLargeError{message: "payload too large"}
+
+	// ErrBodyTooLarge is returned when the request body would exceed
+	// the limit set through WithBodyBufferLimit.
+	ErrBodyTooLarge error = &payloadTooLargeError{message: "request body too large"}
+)
+
+// classifyPayloadTooLarge joins ErrPayloadTooLarge to the
+// error of a request rejected by the service as too large.
+func classifyPayloadTooLarge(err error, statusCode int) error {
+	if statusCode != http.StatusRequestEntityTooLarge {
+		return err
+	}
+	return &classifiedError{sentinel: ErrPayloadTooLarge, err: err}
+}
+
+// checkBodySize returns ErrBodyTooLarge when the request body would
+// exceed the body buffer limit, before the documents are encoded. The
+// size of the body is that of the request marshalled without the
+// documents, plus that of the encoded documents, which JSON leaves
+// unescaped.
+func (r *resumeParsingServiceClient) checkBodySize(request any, docs ...[]byte) error {
+	if r.bodyBufferLimit <= 0 {
+		return nil
+	}
+	j, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "measuring request body")
+	}
+	size := len(j)
+	for _, doc := range docs {
+		size += r.base64Encoding.EncodedLen(len(doc))
+	}
+	if size > r.bodyBufferLimit {
+		return errors.Wrapf(ErrBodyTooLarge, "%d-byte request body exceeds the limit of %d", size, r.bodyBufferLimit)
+	}
+	return nil
+}
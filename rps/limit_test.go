@@ -0,0 +1,121 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithBodyBufferLimit(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		fileContents     []byte
+		expectedBodySize int
+		expectedError    error
+	}{
+		{
+			name:         "no limit by default",
+			fileContents: make([]byte, 1<<20),
+		},
+		{
+			name:             "at the limit",
+			options:          []Option{WithBodyBufferLimit(26)},
+			fileContents:     []byte("123456"),
+			expectedBodySize: 26,
+		},
+		{
+			name:          "over the limit",
+			options:       []Option{WithBodyBufferLimit(26)},
+			fileContents:  []byte("1234567"),
+			expectedError: errors.New("30-byte request body exceeds the limit of 26: request body too large"),
+		},
+		{
+			name:          "request fields count toward the limit",
+			options:       []Option{WithBodyBufferLimit(26), WithOCR(true)},
+			fileContents:  []byte("123456"),
+			expectedError: errors.New("37-byte request body exceeds the limit of 26: request body too large"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			_, err := client.ParseDocument(context.TODO(), tc.fileContents)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				require.ErrorIs(t, err, ErrBodyTooLarge)
+				require.Nil(t, mock.Req)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.NotNil(t, mock.Req)
+				if tc.expectedBodySize > 0 {
+					body, err := io.ReadAll(mock.Req.Body)
+					require.Nil(t, err)
+					require.Len(t, body, tc.expectedBodySize)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDocumentBundleWithBodyBufferLimit(t *testing.T) {
+	client := NewResumeParsingServiceClient("TOKEN", "URL", WithBodyBufferLimit(64))
+	_, err := client.ParseDocumentBundle(context.TODO(), [][]byte{[]byte("123"), []byte("1234")})
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	require.Equal(t, "65-byte request body exceeds the limit of 64: request body too large", err.Error())
+}
+
+func TestParseDocumentPayloadTooLarge(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		expectedTooLarge bool
+	}{
+		{
+			name:             "payload too large",
+			statusCode:       http.StatusRequestEntityTooLarge,
+			expectedTooLarge: true,
+		},
+		{
+			name:       "other failure",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			_, err := client.ParseDocument(context.TODO(), []byte("123"))
+			require.NotNil(t, err)
+			require.Equal(t, tc.expectedTooLarge, errors.Is(err, ErrPayloadTooLarge))
+			require.False(t, errors.Is(err, ErrBodyTooLarge))
+			var httpErr *HttpError
+			require.True(t, errors.As(err, &httpErr))
+			require.Equal(t, tc.statusCode, httpErr.StatusCode)
+		})
+	}
+}
+
+func TestBodyTooLargeIsPayloadTooLarge(t *testing.T) {
+	client := NewResumeParsingServiceClient("TOKEN", "URL", WithBodyBufferLimit(8))
+	_, err := client.ParseDocument(context.TODO(), []byte("1234567"))
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+}
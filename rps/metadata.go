@@ -0,0 +1,62 @@
+package rps
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// CallMetadata holds information about a single call,
+// captured through the WithCallMetadata call option.
+type CallMetadata struct {
+	// ClientName is the name of the client that made the call,
+	// as set through WithClientName.
+	ClientName string
+
+	// EncodeDuration is the time spent locally encoding the
+	// document and marshalling the request body.
+	EncodeDuration time.Duration
+
+	// StatusCode is the status code of the final response, or zero
+	// if no response was received, or if the retries were exhausted.
+	StatusCode int
+
+	// LikelyScannedPDF reports whether the document appears to be a PDF
+	// without an extractable text layer, likely needing OCR. It is only
+	// populated when WithScannedPDFDetection is set.
+	LikelyScannedPDF bool
+
+	// Meta is the raw "meta" object of an envelope-wrapped
+	// response. It is only populated when WithResponseEnvelope
+	// is set.
+	Meta json.RawMessage
+
+	// SchemaVersion is the version of the output schema returned by
+	// the service in the schema_version field of the response, so that
+	// callers can detect schema upgrades. It is empty if absent.
+	SchemaVersion string
+
+	// Trailers holds the trailers of the final response, such as
+	// server-side timing or quota information, if any.
+	Trailers http.Header
+}
+
+// observeResponse captures the status code and the
+// trailers of the final response, if any.
+func (md *CallMetadata) observeResponse(resp *http.Response, err error) {
+	md.StatusCode = statusCode(resp, err)
+	if resp != nil {
+		md.Trailers = resp.Trailer
+	}
+}
+
+// WithCallMetadata captures the metadata of the call into md.
+// It is populated even when the call fails.
+func WithCallMetadata(md *CallMetadata) CallOption {
+	return func(c *callOptions) {
+		if md != nil {
+			c.metadata = md
+			c.metadataRequested = true
+		}
+	}
+}
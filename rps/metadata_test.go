@@ -0,0 +1,200 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentEncodeDuration(t *testing.T) {
+	largeInput := bytes.Repeat([]byte("resume"), 4<<20)
+	testCases := []struct {
+		name            string
+		mockJsonMarshal func(v any) ([]byte, error)
+		expectedError   error
+	}{
+		{
+			name: "happy path",
+		},
+		{
+			name: "error when marshalling",
+			mockJsonMarshal: func(v any) ([]byte, error) {
+				return nil, errors.New("marshalling error")
+			},
+			expectedError: errors.New("marshalling parse document request: marshalling error"),
+		},
+	}
+	originalJsonMarshal := jsonMarshal
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				jsonMarshal = originalJsonMarshal
+			}()
+			if tc.mockJsonMarshal != nil {
+				jsonMarshal = tc.mockJsonMarshal
+			}
+			client := newClientWithMock(t, nil)
+			var md CallMetadata
+			_, err := client.ParseDocument(context.TODO(), largeInput, WithCallMetadata(&md))
+			if tc.expectedError != nil {
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+			require.Greater(t, md.EncodeDuration, time.Duration(0))
+		})
+	}
+}
+
+func TestParseDocumentBundleEncodeDuration(t *testing.T) {
+	client := newClientWithMock(t, nil)
+	var md CallMetadata
+	_, _ = client.ParseDocumentBundle(context.TODO(), [][]byte{bytes.Repeat([]byte("resume"), 4<<20)}, WithCallMetadata(&md))
+	require.Greater(t, md.EncodeDuration, time.Duration(0))
+}
+
+func TestParseDocumentTrailers(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		trailers         map[string]string
+		expectedTrailers http.Header
+	}{
+		{
+			name: "no trailers",
+		},
+		{
+			name:             "trailers are captured",
+			trailers:         map[string]string{"X-Processing-Time": "12ms", "X-Quota-Remaining": "99"},
+			expectedTrailers: http.Header{"X-Processing-Time": {"12ms"}, "X-Quota-Remaining": {"99"}},
+		},
+		{
+			name:             "trailers are captured with a negotiated decoder",
+			options:          []Option{WithAcceptEncoding("application/msgpack"), WithDecoder("application/msgpack", fakeMsgpackDecoder)},
+			trailers:         map[string]string{"X-Processing-Time": "12ms"},
+			expectedTrailers: http.Header{"X-Processing-Time": {"12ms"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name := range tc.trailers {
+					w.Header().Add("Trailer", name)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+				for name, value := range tc.trailers {
+					w.Header().Set(name, value)
+				}
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			var md CallMetadata
+			_, err := client.ParseDocument(context.TODO(), []byte{}, WithCallMetadata(&md))
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedTrailers, md.Trailers)
+		})
+	}
+}
+
+func TestParseDocumentClientName(t *testing.T) {
+	testCases := []struct {
+		name               string
+		options            []Option
+		statusCode         int
+		expectedClientName string
+	}{
+		{
+			name:       "unnamed client",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:               "named client",
+			options:            []Option{WithClientName("premium")},
+			statusCode:         http.StatusOK,
+			expectedClientName: "premium",
+		},
+		{
+			name:               "named client on failure",
+			options:            []Option{WithClientName("basic")},
+			statusCode:         http.StatusInternalServerError,
+			expectedClientName: "basic",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			var md CallMetadata
+			_, _ = client.ParseDocument(context.TODO(), []byte{}, WithCallMetadata(&md))
+			require.Equal(t, tc.expectedClientName, md.ClientName)
+			require.Equal(t, tc.statusCode, md.StatusCode)
+		})
+	}
+}
+
+func TestParseDocumentSchemaVersion(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		options               []Option
+		body                  string
+		expectedSchemaVersion string
+	}{
+		{
+			name: "absent",
+			body: `{"first_name":"Morgana"}`,
+		},
+		{
+			name:                  "string",
+			body:                  `{"first_name":"Morgana","schema_version":"2.1"}`,
+			expectedSchemaVersion: "2.1",
+		},
+		{
+			name:                  "number",
+			body:                  `{"schema_version":3,"first_name":"Morgana"}`,
+			expectedSchemaVersion: "3",
+		},
+		{
+			name: "null",
+			body: `{"first_name":"Morgana","schema_version":null}`,
+		},
+		{
+			name:                  "in the envelope metadata",
+			options:               []Option{WithResponseEnvelope("data")},
+			body:                  `{"data":{"first_name":"Morgana"},"meta":{"schema_version":"2.1"}}`,
+			expectedSchemaVersion: "2.1",
+		},
+		{
+			name:                  "in the envelope data",
+			options:               []Option{WithResponseEnvelope("data")},
+			body:                  `{"data":{"first_name":"Morgana","schema_version":"2.1"}}`,
+			expectedSchemaVersion: "2.1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			var md CallMetadata
+			resume, err := client.ParseDocument(context.TODO(), []byte{}, WithCallMetadata(&md))
+			require.Nil(t, err)
+			require.Equal(t, "Morgana", resume.FirstName)
+			require.Equal(t, tc.expectedSchemaVersion, md.SchemaVersion)
+		})
+	}
+}
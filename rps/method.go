@@ -0,0 +1,48 @@
+package rps
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methodOverrideHeader is the header used to pass the method
+// of POST requests to gateways that only accept POST.
+const methodOverrideHeader = "X-HTTP-Method-Override"
+
+// knownHTTPMethods are the methods accepted by WithHTTPMethod
+// and WithHTTPMethodOverride.
+var knownHTTPMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// validHTTPMethod returns the method, in upper case, if it is a known
+// HTTP method. Otherwise, it logs a warning and returns an empty
+// string, so that the default method is used instead.
+func (r *resumeParsingServiceClient) validHTTPMethod(method string) string {
+	if method == "" {
+		return ""
+	}
+	upper := strings.ToUpper(method)
+	if !knownHTTPMethods[upper] {
+		logPrintf("%s: ignoring unknown HTTP method %q", r.logPrefix(), method)
+		return ""
+	}
+	return upper
+}
+
+// requestMethod returns the method of the requests to
+// the service, POST unless set through WithHTTPMethod.
+func (r *resumeParsingServiceClient) requestMethod() string {
+	if r.httpMethod == "" {
+		return http.MethodPost
+	}
+	return r.httpMethod
+}
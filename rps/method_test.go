@@ -0,0 +1,78 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithHTTPMethod(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		expectedMethod   string
+		expectedOverride string
+		expectedWarnings []string
+	}{
+		{
+			name:           "post by default",
+			expectedMethod: http.MethodPost,
+		},
+		{
+			name:           "method",
+			options:        []Option{WithHTTPMethod(http.MethodPut)},
+			expectedMethod: http.MethodPut,
+		},
+		{
+			name:           "method is case-insensitive",
+			options:        []Option{WithHTTPMethod("put")},
+			expectedMethod: http.MethodPut,
+		},
+		{
+			name:             "method override header",
+			options:          []Option{WithHTTPMethodOverride(http.MethodPut)},
+			expectedMethod:   http.MethodPost,
+			expectedOverride: http.MethodPut,
+		},
+		{
+			name:             "unknown method is ignored",
+			options:          []Option{WithHTTPMethod("FETCH")},
+			expectedMethod:   http.MethodPost,
+			expectedWarnings: []string{`rps: ignoring unknown HTTP method "FETCH"`},
+		},
+		{
+			name:             "unknown method override is ignored",
+			options:          []Option{WithHTTPMethodOverride("FETCH")},
+			expectedMethod:   http.MethodPost,
+			expectedWarnings: []string{`rps: ignoring unknown HTTP method "FETCH"`},
+		},
+	}
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []string
+			logPrintf = func(format string, v ...any) {
+				warnings = append(warnings, fmt.Sprintf(format, v...))
+			}
+			var method, override string
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				override = r.Header.Get(methodOverrideHeader)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedMethod, method)
+			require.Equal(t, tc.expectedOverride, override)
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
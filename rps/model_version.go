@@ -0,0 +1,81 @@
+package rps
+
+import "encoding/json"
+
+// Versions of the resume model of the responses, selected through
+// WithModelVersion. Whatever the version, responses are decoded into
+// Resume, so that callers keep accessing the same fields.
+const (
+	// ModelV1 is the current model, whose shape Resume follows.
+	ModelV1 = 1
+
+	// ModelV2 groups the names of the candidate under "name", and
+	// the contact details under "contact", e.g.
+	//
+	//	{"name": {"first": "Morgana", "last": "Favero"},
+	//	 "contact": {"emails": ["favero.morgana@gmail.com"]}, ...}
+	ModelV2 = 2
+)
+
+// validModelVersion returns the model version if it is known. Otherwise,
+// it logs a warning and returns ModelV1, the current model.
+func (r *resumeParsingServiceClient) validModelVersion(version int) int {
+	switch version {
+	case 0:
+		return ModelV1
+	case ModelV1, ModelV2:
+		return version
+	default:
+		logPrintf("%s: ignoring unknown model version %d", r.logPrefix(), version)
+		return ModelV1
+	}
+}
+
+// modelFor returns the value to decode a response into, which is
+// target itself unless a *Resume is decoded from another model version.
+func (r *resumeParsingServiceClient) modelFor(target any) any {
+	resume, ok := target.(*Resume)
+	if !ok || r.modelVersion != ModelV2 {
+		return target
+	}
+	return &resumeModelV2{resume: resume}
+}
+
+// resumeModelV2 decodes a resume of the version 2 of the model.
+type resumeModelV2 struct {
+	resume *Resume
+}
+
+// UnmarshalJSON decodes the fields shared with the version 1 of the
+// model as they are, then the grouped names and contact details.
+func (m *resumeModelV2) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, m.resume); err != nil {
+		return err
+	}
+	var grouped struct {
+		Name *struct {
+			First  string `json:"first"`
+			Middle string `json:"middle"`
+			Last   string `json:"last"`
+		} `json:"name"`
+		Contact *struct {
+			Emails       []string      `json:"emails"`
+			PhoneNumbers []PhoneNumber `json:"phone_numbers"`
+			SocialUrls   []SocialUrl   `json:"social_urls"`
+			Location     Location      `json:"location"`
+		} `json:"contact"`
+	}
+	if err := json.Unmarshal(data, &grouped); err != nil {
+		return err
+	}
+	if name := grouped.Name; name != nil {
+		m.resume.FirstName, m.resume.MiddleName, m.resume.LastName = name.First, name.Middle, name.Last
+	}
+	if contact := grouped.Contact; contact != nil {
+		m.resume.Emails = contact.Emails
+		m.resume.PhoneNumbers = contact.PhoneNumbers
+		m.resume.SocialUrls = contact.SocialUrls
+		m.resume.Location = contact.Location
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithModelVersion(t *testing.T) {
+	expected := &Resume{
+		FirstName: "Morgana",
+		LastName:  "Favero",
+		Emails:    []string{"favero.morgana@gmail.com"},
+		Skills:    []Skill{{Name: "Go", NumMonths: 24}},
+	}
+	v1 := `{"first_name":"Morgana","last_name":"Favero","emails":["favero.morgana@gmail.com"],"skills":[{"name":"Go","num_months":24}]}`
+	v2 := `{"name":{"first":"Morgana","last":"Favero"},"contact":{"emails":["favero.morgana@gmail.com"]},"skills":[{"name":"Go","num_months":24}]}`
+
+	testCases := []struct {
+		name    string
+		options []Option
+		body    string
+	}{
+		{
+			name: "version 1 by default",
+			body: v1,
+		},
+		{
+			name:    "version 1",
+			options: []Option{WithModelVersion(ModelV1)},
+			body:    v1,
+		},
+		{
+			name:    "version 2",
+			options: []Option{WithModelVersion(ModelV2)},
+			body:    v2,
+		},
+		{
+			name:    "unknown version falls back to version 1",
+			options: []Option{WithModelVersion(42)},
+			body:    v1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, ok := NewResumeParsingServiceClient("TOKEN", "URL", tc.options...).(*resumeParsingServiceClient)
+			require.True(t, ok)
+			client.httpClient = &staticHttpClient{body: []byte(tc.body)}
+
+			resume, err := client.ParseDocument(context.TODO(), []byte("file"))
+			require.NoError(t, err)
+			require.Equal(t, expected.FirstName, resume.FirstName)
+			require.Equal(t, expected.LastName, resume.LastName)
+			require.Equal(t, expected.Emails, resume.Emails)
+			require.Equal(t, expected.Skills, resume.Skills)
+		})
+	}
+}
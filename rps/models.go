@@ -2,6 +2,9 @@ package rps
 
 import "time"
 
+// Resume is a resume parsed by the service. Its lists, e.g. Languages,
+// are in the order of the response: the client only reorders them when
+// asked to, e.g. through WithDeterministicSkillOrder or WithMaxPositions.
 type Resume struct {
 	FirstName        string        `json:"first_name"`
 	MiddleName       string        `json:"middle_name"`
@@ -19,6 +22,12 @@ type Resume struct {
 	DetectedLanguage string        `json:"detected_language"`
 	Skills           []Skill       `json:"skills"`
 	RawText          string        `json:"raw_text"`
+	// FieldConfidence is the extraction confidence of each field,
+	// between 0 and 1, nil if the service does not return it.
+	FieldConfidence Confidences `json:"confidence"`
+	// Extensions holds the fields that the library does not model,
+	// e.g. set by an unmarshal interceptor from the raw response.
+	Extensions map[string]any `json:"-"`
 }
 
 type Position struct {
@@ -30,6 +39,7 @@ type Position struct {
 	Description     string     `json:"description"`
 	Location        Location   `json:"location"`
 	ManagementLevel string     `json:"management_level"`
+	Confidence      float64    `json:"confidence"`
 }
 
 type Education struct {
@@ -39,6 +49,7 @@ type Education struct {
 	EndDate        *time.Time `json:"end_date"`
 	Location       Location   `json:"location"`
 	EducationLevel string     `json:"education_level"`
+	Confidence     float64    `json:"confidence"`
 }
 
 type SocialUrl struct {
@@ -51,11 +62,20 @@ type PhoneNumber struct {
 	CountryCode    string `json:"country_code"`
 	CountryName    string `json:"country_name"`
 	NationalNumber string `json:"national_number"`
+	// defaultRegion is the region set through WithPhoneDefaultRegion,
+	// used by E164 when there is no country code.
+	defaultRegion string
 }
 
 type Skill struct {
 	Name      string `json:"name"`
 	NumMonths int    `json:"num_months"`
+	// Category is the category of the skill (e.g. technical or soft
+	// skill), empty if the service does not return it.
+	Category string `json:"category"`
+	// Aliases are the other names of the skill (e.g. Golang for Go),
+	// nil if the service does not return them.
+	Aliases []string `json:"aliases"`
 }
 
 type Location struct {
@@ -69,4 +89,19 @@ type Location struct {
 
 type parseDocumentRequest struct {
 	Base64Data string `json:"base64_data"`
+	OCR        *bool  `json:"ocr,omitempty"`
+}
+
+type parseDocumentURLRequest struct {
+	URL string `json:"url"`
+	OCR *bool  `json:"ocr,omitempty"`
+}
+
+type parseDocumentBundleRequest struct {
+	Documents []parseDocumentRequest `json:"documents"`
+}
+
+type parseDocumentBundleResult struct {
+	Resume *Resume `json:"resume"`
+	Error  string  `json:"error"`
 }
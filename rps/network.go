@@ -0,0 +1,89 @@
+package rps
+
+import (
+	"crypto/tls"
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkErrorKind is the kind of network failure
+// of a request that received no response.
+type NetworkErrorKind string
+
+// Kinds of network failures.
+const (
+	NetworkErrorDNS               NetworkErrorKind = "dns"
+	NetworkErrorTLS               NetworkErrorKind = "tls"
+	NetworkErrorConnectionRefused NetworkErrorKind = "connection_refused"
+	NetworkErrorConnection        NetworkErrorKind = "connection"
+)
+
+// NetworkError is returned when a request failed without a response
+// because of the network, so that callers can alert differently
+// depending on its kind.
+type NetworkError struct {
+	Kind NetworkErrorKind
+	Err  error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkErrorKindOf returns the kind of the network
+// failure behind the error, if any.
+func NetworkErrorKindOf(err error) (NetworkErrorKind, bool) {
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		return "", false
+	}
+	return netErr.Kind, true
+}
+
+// networkErrorKind returns the kind of the network failure
+// behind the error, if any.
+func networkErrorKind(err error) (NetworkErrorKind, bool) {
+	var dnsErr *net.DNSError
+	var recordHeaderErr tls.RecordHeaderError
+	var certificateErr *tls.CertificateVerificationError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &dnsErr):
+		return NetworkErrorDNS, true
+	case errors.As(err, &recordHeaderErr), errors.As(err, &certificateErr):
+		return NetworkErrorTLS, true
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return NetworkErrorConnectionRefused, true
+	case errors.As(err, &opErr):
+		return NetworkErrorConnection, true
+	default:
+		return "", false
+	}
+}
+
+// classifyNetworkError wraps the error of a request that received
+// no response into a *NetworkError when caused by the network.
+func classifyNetworkError(err error, statusCode int) error {
+	if statusCode != 0 {
+		return err
+	}
+	kind, ok := networkErrorKind(err)
+	if !ok {
+		return err
+	}
+	return &NetworkError{Kind: kind, Err: err}
+}
+
+// classifyRequestError classifies the error of a request as a network
+// failure, as a timeout and as a payload too large, if applicable.
+func classifyRequestError(err error, statusCode int) error {
+	return classifyPayloadTooLarge(classifyTimeout(classifyNetworkError(err, statusCode), statusCode), statusCode)
+}
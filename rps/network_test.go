@@ -0,0 +1,110 @@
+package rps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyNetworkError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		statusCode   int
+		expectedKind NetworkErrorKind
+	}{
+		{
+			name:         "dns failure",
+			err:          &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "rps.invalid", IsNotFound: true}},
+			expectedKind: NetworkErrorDNS,
+		},
+		{
+			name:         "tls record header",
+			err:          tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"},
+			expectedKind: NetworkErrorTLS,
+		},
+		{
+			name:         "tls certificate verification",
+			err:          &tls.CertificateVerificationError{Err: errors.New("unknown authority")},
+			expectedKind: NetworkErrorTLS,
+		},
+		{
+			name:         "connection refused",
+			err:          &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}},
+			expectedKind: NetworkErrorConnectionRefused,
+		},
+		{
+			name:         "connection reset",
+			err:          &net.OpError{Op: "read", Net: "tcp", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}},
+			expectedKind: NetworkErrorConnection,
+		},
+		{
+			name: "not a network failure",
+			err:  errors.New("unexpected end of JSON input"),
+		},
+		{
+			name:       "response received",
+			err:        &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classifyNetworkError(tc.err, tc.statusCode)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.err.Error(), err.Error())
+			kind, ok := NetworkErrorKindOf(err)
+			require.Equal(t, tc.expectedKind != "", ok)
+			require.Equal(t, tc.expectedKind, kind)
+		})
+	}
+}
+
+func TestParseDocumentNetworkErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		start        func(svr *httptest.Server)
+		expectedKind NetworkErrorKind
+	}{
+		{
+			name: "connection refused",
+			start: func(svr *httptest.Server) {
+				svr.Start()
+				svr.Close()
+			},
+			expectedKind: NetworkErrorConnectionRefused,
+		},
+		{
+			name: "untrusted certificate",
+			start: func(svr *httptest.Server) {
+				svr.StartTLS()
+			},
+			expectedKind: NetworkErrorTLS,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			tc.start(svr)
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.NotNil(t, err)
+			kind, ok := NetworkErrorKindOf(err)
+			require.True(t, ok, "unexpected error: %v", err)
+			require.Equal(t, tc.expectedKind, kind)
+			require.False(t, IsTimeout(err))
+		})
+	}
+}
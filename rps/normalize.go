@@ -0,0 +1,108 @@
+package rps
+
+import (
+	"sort"
+	"strings"
+)
+
+// gmailDomains lists the domains whose local part ignores
+// dots and plus-addressing.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// normalizeEmail lowercases and trims an email address. If
+// `gmail` is true, dots and plus-addressing are stripped from
+// Gmail addresses.
+func normalizeEmail(email string, gmail bool) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !gmail {
+		return email
+	}
+	local, domain, found := strings.Cut(email, "@")
+	if !found || !gmailDomains[domain] {
+		return email
+	}
+	local, _, _ = strings.Cut(local, "+")
+	local = strings.ReplaceAll(local, ".", "")
+	return local + "@gmail.com"
+}
+
+// normalizeEmails normalizes and dedupes emails, preserving
+// the order of first occurrence. Empty emails are dropped.
+func normalizeEmails(emails []string, gmail bool) []string {
+	if emails == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(emails))
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		email = normalizeEmail(email, gmail)
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+		normalized = append(normalized, email)
+	}
+	return normalized
+}
+
+// sortSkills sorts skills by NumMonths in descending order,
+// then by name in ascending order.
+func sortSkills(skills []Skill) {
+	sort.SliceStable(skills, func(i, j int) bool {
+		if skills[i].NumMonths != skills[j].NumMonths {
+			return skills[i].NumMonths > skills[j].NumMonths
+		}
+		return skills[i].Name < skills[j].Name
+	})
+}
+
+// canonicalSkillNames lowercases the keys of the mapping from skill
+// names to their canonical forms, so that lookups ignore case.
+func canonicalSkillNames(mapping map[string]string) map[string]string {
+	canonical := make(map[string]string, len(mapping))
+	for name, canonicalName := range mapping {
+		canonical[strings.ToLower(strings.TrimSpace(name))] = canonicalName
+	}
+	return canonical
+}
+
+// canonicalizeSkills renames the skills to their canonical forms and
+// merges the skills sharing a name.
+func canonicalizeSkills(skills []Skill, canonical map[string]string) []Skill {
+	if skills == nil {
+		return nil
+	}
+	renamed := make([]Skill, len(skills))
+	for i, skill := range skills {
+		if name, found := canonical[strings.ToLower(strings.TrimSpace(skill.Name))]; found {
+			skill.Name = name
+		}
+		renamed[i] = skill
+	}
+	// The names map to the same skill, so their experience adds up.
+	return mergeSkills(renamed, func(name string) string { return name }, func(a, b int) int { return a + b })
+}
+
+// mergeSkills merges the skills whose names share the same key into
+// the first one, preserving the order of first occurrence. The NumMonths
+// of the merged skills are combined through numMonths.
+func mergeSkills(skills []Skill, key func(name string) string, numMonths func(a, b int) int) []Skill {
+	if skills == nil {
+		return nil
+	}
+	indexes := make(map[string]int, len(skills))
+	merged := make([]Skill, 0, len(skills))
+	for _, skill := range skills {
+		k := key(skill.Name)
+		if i, found := indexes[k]; found {
+			merged[i].NumMonths = numMonths(merged[i].NumMonths, skill.NumMonths)
+			continue
+		}
+		indexes[k] = len(merged)
+		merged = append(merged, skill)
+	}
+	return merged
+}
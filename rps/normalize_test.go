@@ -0,0 +1,188 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		emails         []string
+		gmail          bool
+		expectedOutput []string
+	}{
+		{
+			name: "nil emails",
+		},
+		{
+			name:           "lowercases and trims",
+			emails:         []string{" John.Doe@Example.COM ", "jane@example.com"},
+			expectedOutput: []string{"john.doe@example.com", "jane@example.com"},
+		},
+		{
+			name:           "dedupes preserving first occurrence",
+			emails:         []string{"b@example.com", "A@example.com", "B@EXAMPLE.COM", "a@example.com", ""},
+			expectedOutput: []string{"b@example.com", "a@example.com"},
+		},
+		{
+			name:           "gmail aliases are kept without gmail normalization",
+			emails:         []string{"john.doe+jobs@gmail.com", "johndoe@gmail.com"},
+			expectedOutput: []string{"john.doe+jobs@gmail.com", "johndoe@gmail.com"},
+		},
+		{
+			name:           "gmail aliases are merged with gmail normalization",
+			emails:         []string{"John.Doe+jobs@gmail.com", "johndoe@googlemail.com", "john.doe+jobs@example.com"},
+			gmail:          true,
+			expectedOutput: []string{"johndoe@gmail.com", "john.doe+jobs@example.com"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := normalizeEmails(tc.emails, tc.gmail)
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestParseDocumentWithEmailNormalization(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedEmails []string
+	}{
+		{
+			name:           "no normalization",
+			expectedEmails: []string{"Morgana.Favero+cv@gmail.com", "favero.morgana@gmail.com", "moRgana.favero@gmail.com"},
+		},
+		{
+			name:           "with email normalization",
+			options:        []Option{WithEmailNormalization()},
+			expectedEmails: []string{"morgana.favero+cv@gmail.com", "favero.morgana@gmail.com", "morgana.favero@gmail.com"},
+		},
+		{
+			name:           "with email and gmail normalization",
+			options:        []Option{WithGmailNormalization(), WithEmailNormalization()},
+			expectedEmails: []string{"morganafavero@gmail.com", "faveromorgana@gmail.com"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				Emails: []string{"Morgana.Favero+cv@gmail.com", "favero.morgana@gmail.com", "moRgana.favero@gmail.com"},
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedEmails, resume.Emails)
+		})
+	}
+}
+
+func TestParseDocumentWithDeterministicSkillOrder(t *testing.T) {
+	skills := []Skill{
+		{Name: "Teamwork", NumMonths: 0},
+		{Name: "Research", NumMonths: 80},
+		{Name: "Editing", NumMonths: 0},
+		{Name: "Physiology", NumMonths: 31},
+		{Name: "Collaboration", NumMonths: 31},
+	}
+	shuffled := []Skill{skills[3], skills[0], skills[4], skills[2], skills[1]}
+	testCases := []struct {
+		name           string
+		options        []Option
+		skills         []Skill
+		expectedOutput []Skill
+	}{
+		{
+			name:           "service order is preserved by default",
+			skills:         skills,
+			expectedOutput: skills,
+		},
+		{
+			name:    "sorted skills",
+			options: []Option{WithDeterministicSkillOrder()},
+			skills:  skills,
+			expectedOutput: []Skill{
+				{Name: "Research", NumMonths: 80},
+				{Name: "Collaboration", NumMonths: 31},
+				{Name: "Physiology", NumMonths: 31},
+				{Name: "Editing", NumMonths: 0},
+				{Name: "Teamwork", NumMonths: 0},
+			},
+		},
+		{
+			name:    "shuffled skills are sorted the same way",
+			options: []Option{WithDeterministicSkillOrder()},
+			skills:  shuffled,
+			expectedOutput: []Skill{
+				{Name: "Research", NumMonths: 80},
+				{Name: "Collaboration", NumMonths: 31},
+				{Name: "Physiology", NumMonths: 31},
+				{Name: "Editing", NumMonths: 0},
+				{Name: "Teamwork", NumMonths: 0},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]Skill(nil), tc.skills...)
+			client := newClientWithMock(t, &Resume{Skills: input}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume.Skills)
+		})
+	}
+}
+
+func TestParseDocumentWithSkillCanonicalization(t *testing.T) {
+	mapping := map[string]string{
+		"MS Word": "Microsoft Word",
+		"ms-word": "Microsoft Word",
+		"Golang":  "Go",
+	}
+	testCases := []struct {
+		name           string
+		options        []Option
+		skills         []Skill
+		expectedOutput []Skill
+	}{
+		{
+			name:           "skills are untouched by default",
+			skills:         []Skill{{Name: "MS Word", NumMonths: 12}, {Name: "Golang", NumMonths: 24}},
+			expectedOutput: []Skill{{Name: "MS Word", NumMonths: 12}, {Name: "Golang", NumMonths: 24}},
+		},
+		{
+			name:           "skills are mapped ignoring case",
+			options:        []Option{WithSkillCanonicalization(mapping)},
+			skills:         []Skill{{Name: "ms word", NumMonths: 12}, {Name: " GOLANG ", NumMonths: 24}, {Name: "Research", NumMonths: 6}},
+			expectedOutput: []Skill{{Name: "Microsoft Word", NumMonths: 12}, {Name: "Go", NumMonths: 24}, {Name: "Research", NumMonths: 6}},
+		},
+		{
+			name:    "collisions are merged summing NumMonths",
+			options: []Option{WithSkillCanonicalization(mapping)},
+			skills: []Skill{
+				{Name: "MS Word", NumMonths: 12},
+				{Name: "Go", NumMonths: 10},
+				{Name: "Microsoft Word", NumMonths: 30},
+				{Name: "ms-word", NumMonths: 2},
+				{Name: "Golang", NumMonths: 24},
+			},
+			expectedOutput: []Skill{{Name: "Microsoft Word", NumMonths: 44}, {Name: "Go", NumMonths: 34}},
+		},
+		{
+			name:           "no skills",
+			options:        []Option{WithSkillCanonicalization(mapping)},
+			expectedOutput: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{Skills: tc.skills}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume.Skills)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithTreat404AsEmpty(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		statusCode     int
+		expectedOutput *Resume
+		expectedError  bool
+	}{
+		{
+			name:          "404 is an error by default",
+			statusCode:    http.StatusNotFound,
+			expectedError: true,
+		},
+		{
+			name:           "404 is an empty parse",
+			options:        []Option{WithTreat404AsEmpty()},
+			statusCode:     http.StatusNotFound,
+			expectedOutput: &Resume{},
+		},
+		{
+			name:          "other failures are still errors",
+			options:       []Option{WithTreat404AsEmpty()},
+			statusCode:    http.StatusInternalServerError,
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(`{"error":"no text extracted"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			for _, parse := range []func() (*Resume, error){
+				func() (*Resume, error) {
+					return client.ParseDocument(context.TODO(), []byte{})
+				},
+				func() (*Resume, error) {
+					return client.ParseDocumentStreaming(context.TODO(), []byte{}, nil)
+				},
+			} {
+				output, err := parse()
+				require.Equal(t, tc.expectedError, err != nil)
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
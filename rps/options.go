@@ -1,6 +1,7 @@
 package rps
 
 import (
+	"encoding/base64"
 	"time"
 )
 
@@ -52,6 +53,100 @@ func WithRetryWaitMax(d time.Duration) Option {
 	}
 }
 
+// WithDNSCache resolves the host of the service at most once per
+// TTL, saving repeated DNS lookups under high request rates.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.dnsCacheTTL = ttl
+	}
+}
+
+// WithDualStackDial connects to the service by racing IPv4 and IPv6
+// (Happy Eyeballs) when it is reachable over both, minimizing connection
+// latency on networks with broken IPv6: the other family is dialed when
+// the preferred one has not connected within fallbackDelay (300ms if
+// not positive).
+func WithDualStackDial(fallbackDelay time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.dualStackDial = true
+		c.dualStackFallback = fallbackDelay
+	}
+}
+
+// WithMaxConnLifetime recycles connections older than d, so that load
+// is redistributed across the backends of the service after scaling events.
+func WithMaxConnLifetime(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.maxConnLifetime = d
+	}
+}
+
+// WithIdleConnTimeout closes the pooled connections that stay idle for
+// longer than d, e.g. to match the keep-alive timeout of the service,
+// avoiding "connection reset" errors when reusing stale connections.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.idleConnTimeout = d
+	}
+}
+
+// WithPerEndpointPools keeps a separate connection pool per host of
+// the service, each honoring the configured limits, e.g. so that routing
+// calls to regional endpoints through WithBaseUrlOverride, or failing
+// over between them, does not thrash a single shared pool.
+func WithPerEndpointPools() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.perEndpointPools = true
+	}
+}
+
+// WithHostHealthTracking tracks the failures, errors or 5xx responses,
+// of the host of the base URL and of the fallback base URLs, regional
+// endpoints of the service with the same paths, in order of preference.
+// A host failing 3 times in a row is skipped for 30 seconds, unless
+// changed through WithHostFailureCooldown, its calls, retries included,
+// being sent to the first healthy host instead, so that traffic shifts
+// away from a failing region. Without fallback base URLs there is no
+// host to shift to. It implies WithPerEndpointPools.
+func WithHostHealthTracking(fallbackBaseUrls ...string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.hostHealthTracking = true
+		c.fallbackBaseUrls = fallbackBaseUrls
+	}
+}
+
+// WithHostFailureCooldown specifies the number of failures in a row after
+// which WithHostHealthTracking skips a host, and for how long. Non-positive
+// values keep the defaults, 3 failures and 30 seconds.
+func WithHostFailureCooldown(maxFailures int, cooldown time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.maxHostFailures = maxFailures
+		c.hostCooldown = cooldown
+	}
+}
+
+// WithPerAttemptTimeout limits the time of each attempt, separately from
+// the overall timeout set through the context. Attempts exceeding it are
+// abandoned and retried, up to the maximum number of retries, improving
+// tail latency when one attempt hangs.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.perAttemptTimeout = d
+	}
+}
+
+// WithRequestTimeout limits the overall time of a call, retries and the
+// waits between them included, so that a stalled service does not hang
+// the call, apart from the timeout set through the context. Calls
+// exceeding it fail with an error telling the request timed out. Unlike
+// the Timeout of the underlying http.Client, set by WithPerAttemptTimeout,
+// it is not reset by the retries.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.requestTimeout = d
+	}
+}
+
 // WithCheckRetryPolicy specifies the policy for handling retries,
 // and is called after each request.
 func WithCheckRetryPolicy(checkRetryPolicy checkRetryPolicy) Option {
@@ -69,3 +164,678 @@ func WithRequestDumpLogger(requestDumpLogger func(dump []byte), dumpRequestBody
 		c.dumpRequestBody = dumpRequestBody
 	}
 }
+
+// WithEmailNormalization lowercases, trims and dedupes the
+// resume emails after decoding, preserving the order of
+// first occurrence.
+func WithEmailNormalization() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			resume.Emails = normalizeEmails(resume.Emails, c.gmailNormalization)
+		})
+	}
+}
+
+// WithGmailNormalization strips dots and plus-addressing from
+// Gmail addresses. It only takes effect along with
+// WithEmailNormalization.
+func WithGmailNormalization() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.gmailNormalization = true
+	}
+}
+
+// WithPhoneDefaultRegion specifies the region (e.g. "US") whose
+// calling code is used by PhoneNumber.E164 as a fallback for phone
+// numbers decoded without a country code, dropping their trunk prefix
+// (e.g. the leading 0 of "020 7946 0000" in GB). The country code of
+// the phone numbers is left as decoded.
+func WithPhoneDefaultRegion(region string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			setDefaultRegion(resume.PhoneNumbers, region)
+		})
+	}
+}
+
+// WithDeterministicSkillOrder sorts the resume skills by NumMonths
+// in descending order, then by name in ascending order. Without it,
+// the order returned by the service is preserved.
+func WithDeterministicSkillOrder() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			sortSkills(resume.Skills)
+		})
+	}
+}
+
+// WithSkillCanonicalization renames the resume skills to the
+// canonical forms given by the mapping (e.g. "MS Word" to
+// "Microsoft Word"), ignoring case. Skills sharing a canonical name are
+// merged into the first one, summing their NumMonths.
+func WithSkillCanonicalization(mapping map[string]string) Option {
+	canonical := canonicalSkillNames(mapping)
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			resume.Skills = canonicalizeSkills(resume.Skills, canonical)
+		})
+	}
+}
+
+// WithNormalizers specifies normalizers, such as DedupSkills,
+// TrimOrganizations or NormalizeEmails, that are applied in order after
+// decoding and after the other normalization options. The parse fails
+// if a normalizer returns an error. It can be used multiple times.
+func WithNormalizers(normalizers ...Normalizer) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizerPipeline = append(c.normalizerPipeline, normalizers...)
+	}
+}
+
+// WithBase64Encoding specifies the encoding used for the document
+// contents sent to the service (e.g. base64.URLEncoding or
+// base64.RawStdEncoding). Defaults to base64.StdEncoding.
+func WithBase64Encoding(enc *base64.Encoding) Option {
+	return func(c *resumeParsingServiceClient) {
+		if enc != nil {
+			c.base64Encoding = enc
+		}
+	}
+}
+
+// WithAcceptEncoding specifies the content type (e.g. "application/msgpack")
+// the service is asked to respond with, through the Accept header.
+// Non-JSON responses are decoded with the decoder registered for
+// their content type through WithDecoder. Defaults to "application/json".
+func WithAcceptEncoding(contentType string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.acceptContentType = contentType
+	}
+}
+
+// WithDecoder registers the decoder used for responses
+// of the given content type.
+func WithDecoder(contentType string, decoder Decoder) Option {
+	return func(c *resumeParsingServiceClient) {
+		if c.decoders == nil {
+			c.decoders = make(map[string]Decoder)
+		}
+		c.decoders[mediaType(contentType)] = decoder
+	}
+}
+
+// WithMinEntryConfidence drops the positions and educations whose
+// confidence is below the given threshold. Entries decoded without
+// a confidence score are assumed to have a confidence of 1.0.
+func WithMinEntryConfidence(f float64) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			filterByConfidence(resume, f)
+		})
+	}
+}
+
+// WithRetryableStatusCodes specifies the status codes that are retried,
+// as a declarative alternative to WithCheckRetryPolicy. Status codes in
+// `noRetry` are never retried, taking precedence over `retry`, e.g. to
+// retry all 5xx except 501:
+//
+//	WithRetryableStatusCodes(StatusCodeRange(500, 599), []int{http.StatusNotImplemented})
+func WithRetryableStatusCodes(retry []int, noRetry []int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.checkRetryPolicy = statusCodesRetryPolicy(retry, noRetry)
+	}
+}
+
+// WithStripHTML removes HTML tags left over from the source
+// document from the resume summary and position descriptions,
+// leaving plain text.
+func WithStripHTML() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, stripResumeHTML)
+	}
+}
+
+// WithControlCharStripping removes the control characters, such as
+// null bytes and form feeds, from every string field of the resume,
+// except tabs and line breaks, as they break JSON storage or database
+// inserts downstream.
+func WithControlCharStripping() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, stripResumeControlChars)
+	}
+}
+
+// WithNormalizeWhitespace collapses runs of whitespace (spaces, tabs
+// and newlines) into single spaces and trims the given text fields.
+// If no field is given, every string field of the resume is normalized.
+func WithNormalizeWhitespace(fields ...TextField) Option {
+	// Copied so that the caller cannot modify them during calls.
+	fields = append([]TextField(nil), fields...)
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			normalizeResumeWhitespace(resume, fields)
+		})
+	}
+}
+
+// WithLatencyHistogram specifies a function that is called once per
+// ParseDocument call, on both success and failure, with its total
+// duration (including retries), the final status code (zero if no
+// response was received, or if the retries were exhausted) and the name
+// of the client (see WithClientName), e.g. to compute latency
+// percentiles for SLOs.
+func WithLatencyHistogram(observe func(d time.Duration, status int, clientName string)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.latencyHistogram = observe
+	}
+}
+
+// WithSlowParseThreshold specifies a function that is called once per
+// ParseDocument call whose total duration (including retries) exceeds
+// the threshold, on both success and failure, with the duration and the
+// name of the client (see WithClientName), e.g. to alert on unusually
+// slow documents.
+func WithSlowParseThreshold(d time.Duration, onSlow func(dur time.Duration, clientName string)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.slowParseThreshold = d
+		c.onSlowParse = onSlow
+	}
+}
+
+// WithResponseHook specifies a function that is called after each
+// successful parse with both the decoded resume and the raw response
+// body, e.g. to dual-write to a warehouse (raw) and an app (struct).
+func WithResponseHook(hook func(resume *Resume, raw []byte)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.responseHook = hook
+	}
+}
+
+// WithUnmarshalInterceptor specifies a function that is called after
+// the default decode of each parsed resume, before normalization, with
+// the raw response body, e.g. to read keys that the library does not
+// model into Resume.Extensions. An error fails the parse.
+func WithUnmarshalInterceptor(interceptor func(raw []byte, resume *Resume) error) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.unmarshalInterceptor = interceptor
+	}
+}
+
+// WithScannedPDFDetection enables a best-effort local check of whether
+// a PDF document appears to have no extractable text layer, e.g. a
+// scanned image, which is reported through CallMetadata.LikelyScannedPDF.
+func WithScannedPDFDetection() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.scannedPDFDetection = true
+	}
+}
+
+// WithOCR specifies whether the service is asked to run OCR on the
+// documents, trading latency for coverage of image-based documents.
+// Along with WithScannedPDFDetection, OCR is only requested for the
+// documents that look scanned.
+func WithOCR(enabled bool) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.ocr = &enabled
+	}
+}
+
+// WithConnectionCloseAfterRequest sends a "Connection: close" header
+// and closes the connection after each request, for one-shot usage
+// (e.g. CLI tools parsing a single file) that needs no keep-alive.
+func WithConnectionCloseAfterRequest() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.closeConnection = true
+	}
+}
+
+// WithServiceVersion pins the schema version the service responds with,
+// through the Accept-Version header, so that server upgrades do not
+// break decoding.
+func WithServiceVersion(v string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.serviceVersion = v
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests, e.g.
+// "candidate-import/1.4", so that the service sending them can be told
+// apart in the access logs of the Resume Parsing Service. Requests go out
+// with the default User-Agent of net/http otherwise.
+func WithUserAgent(ua string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.userAgent = ua
+	}
+}
+
+// WithHeader sets a custom header on every request, e.g. a tenant
+// identifier. The token header cannot be set this way, and is ignored
+// with a warning: use WithContextToken to vary the token instead.
+func WithHeader(key, value string) Option {
+	return func(c *resumeParsingServiceClient) {
+		if c.headers == nil {
+			c.headers = make(map[string]string)
+		}
+		c.headers[key] = value
+	}
+}
+
+// WithHeaders sets the given custom headers on every request, like
+// WithHeader for each of them.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *resumeParsingServiceClient) {
+		for key, value := range headers {
+			WithHeader(key, value)(c)
+		}
+	}
+}
+
+// WithResponseEnvelope unwraps the given key of JSON responses wrapped as
+// {"data": {...}, "meta": {...}} before decoding, exposing the "meta"
+// object through CallMetadata. By default, responses are not wrapped.
+func WithResponseEnvelope(dataKey string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.envelopeDataKey = dataKey
+	}
+}
+
+// WithContextToken reads the token from the request context under the
+// given key, overriding the static token per call, e.g. for multi-tenant
+// credentials. The value stored under key must be a string, and key should
+// be of an unexported type to avoid collisions, as for context.WithValue.
+// The static token is used when the context holds no token.
+func WithContextToken(key any) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.contextTokenKey = key
+	}
+}
+
+// WithBodyBufferLimit caps the size in bytes of the request bodies held
+// in memory, i.e. the encoded documents along with the rest of the JSON
+// payload. Calls whose body would exceed the limit fail with
+// ErrBodyTooLarge before the documents are encoded. By default, there
+// is no limit.
+func WithBodyBufferLimit(bytes int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.bodyBufferLimit = bytes
+	}
+}
+
+// WithRequestValidator runs the given function on each document before
+// it is encoded, e.g. to enforce a minimum size or required magic bytes.
+// When it returns an error, the call fails with that error without a
+// request being sent.
+func WithRequestValidator(validator func(fileContents []byte) error) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.requestValidator = validator
+	}
+}
+
+// WithMinimumViableResume checks each parsed resume once normalized,
+// failing the call with ErrUnusableResume when viable returns false,
+// e.g. to reject the resumes having neither positions nor educations
+// rather than storing garbage parses.
+func WithMinimumViableResume(viable func(resume *Resume) bool) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.viableResume = viable
+	}
+}
+
+// WithModelVersion selects the version of the resume model of the
+// responses, ModelV1 by default, e.g. ModelV2 when the service emits the
+// newer shape. Responses are decoded into Resume whatever the version,
+// so that callers keep accessing the same fields. It applies to the JSON
+// responses to single documents. Unknown versions are ignored.
+func WithModelVersion(v int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.modelVersion = v
+	}
+}
+
+// WithJSONSchemaValidation validates the raw JSON response body against
+// the given JSON Schema before decoding, failing with an error listing the
+// violations. This catches regressions of the service contract. A schema
+// that does not compile is logged and ignored when creating the client.
+func WithJSONSchemaValidation(schema []byte) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.responseSchemaSource = schema
+	}
+}
+
+// WithDeadlinePropagationHeader passes the time left before the deadline
+// of the request context, in milliseconds, through the X-Deadline header,
+// so that the service can abort in sync with the client instead of doing
+// wasted work. The header is omitted when the context has no deadline,
+// and set again before each retry with the time left then.
+func WithDeadlinePropagationHeader() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.deadlinePropagation = true
+	}
+}
+
+// WithMaxSkillNameLength drops the skills whose name is longer than n
+// characters, such as entire sentences produced by malformed parses,
+// which clutter the display of the skills. It is ignored unless n is
+// positive.
+func WithMaxSkillNameLength(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		if n > 0 {
+			c.normalizers = append(c.normalizers, func(resume *Resume) {
+				dropLongSkills(resume, n)
+			})
+		}
+	}
+}
+
+// WithDateLocale accepts position and education dates written with
+// slashes, e.g. 03/04/2015. They are read while decoding, in the order
+// of the given locale: day first, e.g. for "it-IT" or "en-GB", or month
+// first, e.g. for "en-US", unless only the other order is valid. Without
+// it, such dates fail the decoding of the resume.
+func WithDateLocale(locale string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.dateLocale = locale
+	}
+}
+
+// WithDateTimezone converts the dates of the positions and educations
+// to the given location once decoded, e.g. time.UTC, so that comparisons
+// and formatting are consistent whatever the zone the service emits them
+// in. The instants are unchanged. Dates are left as decoded without it.
+func WithDateTimezone(loc *time.Location) Option {
+	return func(c *resumeParsingServiceClient) {
+		if loc != nil {
+			c.normalizers = append(c.normalizers, dateTimezoneNormalizer(loc))
+		}
+	}
+}
+
+// WithUSStateNormalization replaces the states of the US locations
+// (going by their country) with their two-letter codes, e.g. "PA" for
+// "Pennsylvania", for consistent filtering. The states of other countries
+// are left untouched. See USStateName for the way back.
+func WithUSStateNormalization() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, normalizeResumeUSStates)
+	}
+}
+
+// WithCollapseAdjacentPositions merges the consecutive positions that
+// the parser split from a single role, i.e. with the same title and
+// organization, where one starts when the other ends, within a month.
+// Undated and overlapping positions are never merged, to avoid merging
+// distinct roles.
+func WithCollapseAdjacentPositions() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, collapseAdjacentPositions)
+	}
+}
+
+// WithMaxPositions sorts the positions by start date in descending
+// order and keeps the n most recent ones, bounding the payloads of
+// resumes with very long histories. Undated positions come last.
+func WithMaxPositions(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			truncatePositions(resume, n)
+		})
+	}
+}
+
+// WithMaxEducations sorts the educations by start date in descending
+// order and keeps the n most recent ones. Undated educations come last.
+func WithMaxEducations(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizers = append(c.normalizers, func(resume *Resume) {
+			truncateEducations(resume, n)
+		})
+	}
+}
+
+// WithResponseChecksumVerification verifies that the response body
+// matches the checksum sent by the service, if any, failing with
+// ErrChecksumMismatch otherwise. This guards against truncated responses
+// from flaky proxies. Both the Content-MD5 header (base64-encoded MD5
+// digest) and the X-Checksum header (hex-encoded SHA-256 digest) are
+// supported.
+func WithResponseChecksumVerification() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.checksumVerification = true
+	}
+}
+
+// WithRequestQueue smooths bursts of ParseDocument calls by releasing
+// them at a steady rate of ratePerSec per second, buffering up to size
+// waiting calls. Further calls block until there is room in the queue
+// or their context is done. Unlike rate limiting, calls are delayed
+// rather than rejected. It is ignored unless both size and ratePerSec
+// are positive.
+func WithRequestQueue(size, ratePerSec int) Option {
+	return func(c *resumeParsingServiceClient) {
+		if size > 0 && ratePerSec > 0 {
+			c.requestQueue = newRequestQueue(size, ratePerSec)
+		}
+	}
+}
+
+// WithLogFieldMasking masks the personal information (emails, phone
+// numbers, street addresses and raw text) of the raw response body
+// passed to the response hook, for compliant logging that still
+// shows the structure of the response.
+func WithLogFieldMasking() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.logFieldMasking = true
+	}
+}
+
+// WithResponseCaptureDir writes each raw response, with its personal
+// information masked, to a timestamped file of the given directory,
+// so that parse issues can be reproduced from the exact payload. Only
+// the 100 most recent responses are kept, to avoid filling the disk.
+func WithResponseCaptureDir(dir string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.responseCapture = &responseCapture{dir: dir}
+	}
+}
+
+// WithRetryBudget specifies how many times each status code is retried,
+// e.g. map[int]int{429: 5, 503: 2} retries 429 up to 5 times but 503 only
+// twice, as an alternative to WithCheckRetryPolicy. Status codes without a
+// budget are not retried. The maximum number of retries is raised to the
+// sum of the budgets if needed.
+func WithRetryBudget(budget map[int]int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.retryBudget = make(map[int]int, len(budget))
+		for statusCode, n := range budget {
+			c.retryBudget[statusCode] = n
+		}
+		c.checkRetryPolicy = retryBudgetPolicy(c.retryBudget)
+	}
+}
+
+// WithRetryOnTimeout retries the attempts that timed out, e.g. exceeding
+// the per-attempt timeout or through a network timeout, up to n times,
+// apart from the status-based retries, which remain bounded by
+// WithMaxRetries. Attempts failing once the context of the call is done
+// are not retried.
+func WithRetryOnTimeout(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.timeoutRetries = n
+	}
+}
+
+// WithRetryOnEmptyBody retries the 200 responses whose body is empty or
+// only holds zero values, working around a transient bug of the service.
+// Other responses are handled by the retry policy. Retries are bounded
+// by WithMaxRetries.
+func WithRetryOnEmptyBody() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.retryOnEmptyBody = true
+	}
+}
+
+// WithBatchProgress specifies a function that is called by
+// ParseDocuments as each document completes, with the number of
+// completed documents and the size of the batch, e.g. to show a progress
+// bar. Calls never overlap, and done increases monotonically.
+func WithBatchProgress(progress func(done, total int)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.batchProgress = progress
+	}
+}
+
+// WithCompressionNegotiation advertises the supported content encodings
+// through the Accept-Encoding header and decompresses the responses
+// accordingly, reducing the download size of large raw-text payloads.
+// Gzip is always supported, and Brotli when built with the "brotli" tag.
+func WithCompressionNegotiation() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.compression = true
+	}
+}
+
+// WithTreat404AsEmpty returns an empty resume instead of an error when
+// the service responds with 404, as some deployments do when no text
+// can be extracted from the document. With ParseDocumentInto, the target
+// is left untouched. Without it, 404 is an error like other failures.
+func WithTreat404AsEmpty() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.treat404AsEmpty = true
+	}
+}
+
+// WithRetryReasonLog specifies a function that is called before each
+// retry with the number of the failed attempt, starting from 1, and a
+// human-readable reason, e.g. "status 503", "connection reset" or
+// "status 429, Retry-After honored".
+func WithRetryReasonLog(log func(attempt int, reason string)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.retryReasonLog = log
+	}
+}
+
+// WithBodyReadIdleTimeout aborts the calls whose response body stalls,
+// i.e. no byte is received for longer than d, with an error matching
+// ErrClientTimeout. Unlike the overall timeout set through the context,
+// slow but steady bodies, such as large raw-text responses, are not aborted.
+func WithBodyReadIdleTimeout(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.bodyReadIdleTimeout = d
+	}
+}
+
+// WithHTTPSUpgrade rewrites an http:// base URL to https:// when the
+// client is created, and the base URLs given through WithBaseUrlOverride
+// when calling, logging a warning, so that this common misconfiguration
+// does not make every call fail opaquely.
+func WithHTTPSUpgrade() Option {
+	return func(c *resumeParsingServiceClient) {
+		c.httpsUpgrade = true
+	}
+}
+
+// WithDecodeTimeout aborts the decoding of responses taking longer
+// than d, e.g. crafted deeply-nested JSON, returning ErrDecodeTimeout.
+// The body is read as it is decoded, hence a body received too slowly
+// is aborted as well.
+func WithDecodeTimeout(d time.Duration) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.decodeTimeout = d
+	}
+}
+
+// WithClientName names the client, e.g. after the tenant tier it serves,
+// to attribute the telemetry of services using several clients: the name
+// is reported in the metadata of each call (see WithCallMetadata), passed
+// to the latency hooks (see WithLatencyHistogram and WithSlowParseThreshold)
+// and tags the logs of the client.
+func WithClientName(name string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.clientName = name
+	}
+}
+
+// WithPricingModel specifies the pricing model used by EstimateCost,
+// e.g. PerPagePricing(0.5). By default, one credit is charged per page.
+func WithPricingModel(pricingModel PricingModel) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.pricingModel = pricingModel
+	}
+}
+
+// WithHookPanicHandler specifies a function that is called with the
+// value of each recovered panic of a user-provided hook, e.g. to report
+// it. Such panics are always recovered and logged, and the parse goes
+// on as if the hook had returned successfully, except for decoders and
+// the minimum viable resume check, whose panics fail the parse.
+func WithHookPanicHandler(handler func(p any)) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.hookPanicHandler = handler
+	}
+}
+
+// WithHTTPMethod specifies the method of the requests to the service,
+// for gateways requiring a different verb than the default POST. Unknown
+// methods are ignored with a warning.
+func WithHTTPMethod(method string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.httpMethod = method
+	}
+}
+
+// WithHTTPMethodOverride passes the given method in the
+// X-HTTP-Method-Override header, for gateways that only accept POST
+// and route on that header. Unknown methods are ignored with a warning.
+func WithHTTPMethodOverride(method string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.httpMethodOverride = method
+	}
+}
+
+// WithNormalizationConcurrency normalizes the resumes of ParseDocuments
+// batches larger than a threshold on a pool of n workers, rather than
+// on the goroutines sending the requests, to speed up the normalization
+// of large batches. Each resume is normalized as soon as it is fetched,
+// and reported to the WithBatchProgress callback once normalized. Results
+// are the same as those of the sequential path.
+func WithNormalizationConcurrency(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.normalizeWorkers = n
+	}
+}
+
+// WithETagCache stores the ETag of each response in the cache, keyed by
+// the hash of the document and of the URL, Accept header, model version
+// and OCR flag of the request, and sends it in the If-None-Match header
+// when the same document is parsed again the same way. On a 304
+// response, the cached resume is returned, saving the service from
+// parsing unchanged documents, e.g. when re-submitting documents to
+// check for service improvements.
+func WithETagCache(cache ETagCache) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.etagCache = cache
+	}
+}
+
+// WithAcceptedDetectedLanguages fails the parse of the resumes whose
+// detected language is not one of the given codes, ignoring case, with
+// a *LanguageNotAcceptedError matching ErrLanguageNotAccepted, so that
+// pipelines handling only some languages skip the others early. Resumes
+// without a detected language are not accepted either. Without any code,
+// every language is accepted.
+func WithAcceptedDetectedLanguages(codes ...string) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.acceptedLanguages = languageCodes(codes)
+	}
+}
+
+// WithDecodeConcurrency decodes the results of ParseDocumentBundle on a
+// pool of n workers, preserving their order, so that decoding large JSON
+// bundles is spread across CPUs in CPU-bound batch workloads. Each
+// result is decoded as set through WithDecoder and WithDecodeTimeout.
+func WithDecodeConcurrency(n int) Option {
+	return func(c *resumeParsingServiceClient) {
+		c.decodeWorkers = n
+	}
+}
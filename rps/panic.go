@@ -0,0 +1,157 @@
+package rps
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// recoverHook recovers from a panic of the user-provided hook of the
+// given name, logging it and passing it to the panic handler, if any,
+// so that a buggy hook does not take down parsing. It must be deferred.
+func (r *resumeParsingServiceClient) recoverHook(name string) {
+	if p := recover(); p != nil {
+		r.handleHookPanic(name, p)
+	}
+}
+
+// recoverHookError recovers from a panic of the user-provided hook of
+// the given name as recoverHook does, and sets err to an error holding
+// the panic, for the hooks the parse cannot go on without. It must be
+// deferred.
+func (r *resumeParsingServiceClient) recoverHookError(name string, err *error) {
+	if p := recover(); p != nil {
+		r.handleHookPanic(name, p)
+		*err = errors.Errorf("recovered from panic in %s: %v", name, p)
+	}
+}
+
+// handleHookPanic logs the panic of the hook of the given
+// name and passes it to the panic handler, if any.
+func (r *resumeParsingServiceClient) handleHookPanic(name string, p any) {
+	logPrintf("%s: recovered from panic in %s: %v", r.logPrefix(), name, p)
+	if r.hookPanicHandler != nil {
+		r.hookPanicHandler(p)
+	}
+}
+
+// guardedETagCache is an ETagCache recovering from
+// the panics of the user-provided one it wraps.
+type guardedETagCache struct {
+	ETagCache
+	client *resumeParsingServiceClient
+}
+
+// Get returns the entry stored under the key, if any.
+// A panicking cache has no entry.
+func (c *guardedETagCache) Get(key string) (entry ETagEntry, ok bool) {
+	defer c.client.recoverHook("ETag cache")
+	return c.ETagCache.Get(key)
+}
+
+// Set stores the entry under the key.
+func (c *guardedETagCache) Set(key string, entry ETagEntry) {
+	defer c.client.recoverHook("ETag cache")
+	c.ETagCache.Set(key, entry)
+}
+
+// guardHooks wraps the user-provided hooks so that their panics are
+// recovered. The parse continues as if a panicking hook had returned
+// successfully, a panicking retry policy does not retry, a panicking
+// ETag cache has no entry, and a panicking pricing model charges
+// nothing. A panicking decoder or minimum viable resume check fails
+// the parse with an error holding the panic (see checkViable).
+func (r *resumeParsingServiceClient) guardHooks() {
+	if observe := r.latencyHistogram; observe != nil {
+		r.latencyHistogram = func(d time.Duration, status int, clientName string) {
+			defer r.recoverHook("latency histogram")
+			observe(d, status, clientName)
+		}
+	}
+	if onSlow := r.onSlowParse; onSlow != nil {
+		r.onSlowParse = func(d time.Duration, clientName string) {
+			defer r.recoverHook("slow parse callback")
+			onSlow(d, clientName)
+		}
+	}
+	if hook := r.responseHook; hook != nil {
+		r.responseHook = func(resume *Resume, raw []byte) {
+			defer r.recoverHook("response hook")
+			hook(resume, raw)
+		}
+	}
+	if validator := r.requestValidator; validator != nil {
+		r.requestValidator = func(fileContents []byte) (err error) {
+			defer r.recoverHook("request validator")
+			return validator(fileContents)
+		}
+	}
+	if interceptor := r.unmarshalInterceptor; interceptor != nil {
+		r.unmarshalInterceptor = func(raw []byte, resume *Resume) (err error) {
+			defer r.recoverHook("unmarshal interceptor")
+			return interceptor(raw, resume)
+		}
+	}
+	for i, normalizer := range r.normalizerPipeline {
+		normalizer := normalizer
+		r.normalizerPipeline[i] = func(resume *Resume) (err error) {
+			defer r.recoverHook("normalizer")
+			return normalizer(resume)
+		}
+	}
+	if progress := r.batchProgress; progress != nil {
+		r.batchProgress = func(done, total int) {
+			defer r.recoverHook("batch progress callback")
+			progress(done, total)
+		}
+	}
+	if log := r.requestDumpLogger; log != nil {
+		r.requestDumpLogger = func(dump []byte) {
+			defer r.recoverHook("request dump logger")
+			log(dump)
+		}
+	}
+	if log := r.retryReasonLog; log != nil {
+		r.retryReasonLog = func(attempt int, reason string) {
+			defer r.recoverHook("retry reason log")
+			log(attempt, reason)
+		}
+	}
+	for contentType, decoder := range r.decoders {
+		decoder := decoder
+		r.decoders[contentType] = DecoderFunc(func(rd io.Reader, v any) (err error) {
+			defer r.recoverHookError("decoder", &err)
+			return decoder.Decode(rd, v)
+		})
+	}
+	if cache := r.etagCache; cache != nil {
+		r.etagCache = &guardedETagCache{ETagCache: cache, client: r}
+	}
+	if pricing := r.pricingModel; pricing != nil {
+		r.pricingModel = func(bytes, pages int) (credits float64) {
+			defer r.recoverHook("pricing model")
+			return pricing(bytes, pages)
+		}
+	}
+	if policy := r.checkRetryPolicy; policy != nil {
+		r.checkRetryPolicy = func(ctx context.Context, resp *http.Response, err error) (retry bool, checkErr error) {
+			defer r.recoverHook("retry policy")
+			return policy(ctx, resp, err)
+		}
+	}
+}
+
+// guardEventCallback wraps the event callback of a streamed
+// parse so that its panics are recovered.
+func (r *resumeParsingServiceClient) guardEventCallback(onEvent func(PartialResume)) func(PartialResume) {
+	if onEvent == nil {
+		return nil
+	}
+	return func(partial PartialResume) {
+		defer r.recoverHook("event callback")
+		onEvent(partial)
+	}
+}
@@ -0,0 +1,216 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithPanickingHook(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     []Option
+		parse       func(client ResumeParsingServiceClient) (*Resume, error)
+		expectedLog string
+	}{
+		{
+			name:        "latency histogram",
+			options:     []Option{WithLatencyHistogram(func(d time.Duration, status int, clientName string) { panic("boom") })},
+			expectedLog: "rps: recovered from panic in latency histogram: boom",
+		},
+		{
+			name:        "slow parse callback",
+			options:     []Option{WithSlowParseThreshold(0, func(d time.Duration, clientName string) { panic("boom") })},
+			expectedLog: "rps: recovered from panic in slow parse callback: boom",
+		},
+		{
+			name:        "response hook",
+			options:     []Option{WithResponseHook(func(resume *Resume, raw []byte) { panic("boom") })},
+			expectedLog: "rps: recovered from panic in response hook: boom",
+		},
+		{
+			name:        "unmarshal interceptor",
+			options:     []Option{WithUnmarshalInterceptor(func(raw []byte, resume *Resume) error { panic("boom") })},
+			expectedLog: "rps: recovered from panic in unmarshal interceptor: boom",
+		},
+		{
+			name:        "normalizer",
+			options:     []Option{WithNormalizers(func(resume *Resume) error { panic("boom") })},
+			expectedLog: "rps: recovered from panic in normalizer: boom",
+		},
+		{
+			name:        "request dump logger",
+			options:     []Option{WithRequestDumpLogger(func(dump []byte) { panic("boom") }, false)},
+			expectedLog: "rps: recovered from panic in request dump logger: boom",
+		},
+		{
+			name: "retry policy",
+			options: []Option{WithCheckRetryPolicy(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+				panic("boom")
+			})},
+			expectedLog: "rps: recovered from panic in retry policy: boom",
+		},
+		{
+			name:    "batch progress callback",
+			options: []Option{WithBatchProgress(func(done, total int) { panic("boom") })},
+			parse: func(client ResumeParsingServiceClient) (*Resume, error) {
+				resumes, err := client.ParseDocumentsAgg(context.TODO(), [][]byte{{}})
+				return resumes[0], err
+			},
+			expectedLog: "rps: recovered from panic in batch progress callback: boom",
+		},
+		{
+			name: "event callback",
+			parse: func(client ResumeParsingServiceClient) (*Resume, error) {
+				return client.ParseDocumentStreaming(context.TODO(), []byte{}, func(PartialResume) { panic("boom") })
+			},
+			expectedLog: "rps: recovered from panic in event callback: boom",
+		},
+		{
+			name:        "ETag cache lookup",
+			options:     []Option{WithETagCache(&panickingETagCache{get: true})},
+			expectedLog: "rps: recovered from panic in ETag cache: boom",
+		},
+		{
+			name:        "ETag cache storage",
+			options:     []Option{WithETagCache(&panickingETagCache{set: true})},
+			expectedLog: "rps: recovered from panic in ETag cache: boom",
+		},
+		{
+			name:        "log is tagged with the client name",
+			options:     []Option{WithClientName("premium"), WithResponseHook(func(resume *Resume, raw []byte) { panic("boom") })},
+			expectedLog: "rps[premium]: recovered from panic in response hook: boom",
+		},
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == eventStreamContentType {
+			w.Header().Set("Content-Type", eventStreamContentType)
+			_, _ = w.Write([]byte("data: {\"first_name\":\"Morgana\"}\n\nevent: complete\ndata: {\"first_name\":\"Morgana\"}\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+	}))
+	defer svr.Close()
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs []string
+			logPrintf = func(format string, v ...any) {
+				logs = append(logs, fmt.Sprintf(format, v...))
+			}
+			var recovered []any
+			options := append(tc.options, WithHookPanicHandler(func(p any) {
+				recovered = append(recovered, p)
+			}))
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			parse := tc.parse
+			if parse == nil {
+				parse = func(client ResumeParsingServiceClient) (*Resume, error) {
+					return client.ParseDocument(context.TODO(), []byte{})
+				}
+			}
+			resume, err := parse(client)
+			require.Nil(t, err)
+			require.Equal(t, "Morgana", resume.FirstName)
+			require.Equal(t, []string{tc.expectedLog}, logs)
+			require.Equal(t, []any{"boom"}, recovered)
+		})
+	}
+}
+
+// panickingETagCache is an ETagCache panicking on lookup, on storage,
+// or both, and otherwise holding no entry.
+type panickingETagCache struct {
+	get bool
+	set bool
+}
+
+func (c *panickingETagCache) Get(key string) (ETagEntry, bool) {
+	if c.get {
+		panic("boom")
+	}
+	return ETagEntry{}, false
+}
+
+func (c *panickingETagCache) Set(key string, entry ETagEntry) {
+	if c.set {
+		panic("boom")
+	}
+}
+
+func TestParseDocumentWithPanickingCheck(t *testing.T) {
+	panickingDecoder := DecoderFunc(func(r io.Reader, v any) error { panic("boom") })
+	testCases := []struct {
+		name          string
+		options       []Option
+		expectedError string
+	}{
+		{
+			name:          "decoder",
+			options:       []Option{WithDecoder("application/json", panickingDecoder)},
+			expectedError: "recovered from panic in decoder: boom",
+		},
+		{
+			name: "decoder within the decode timeout",
+			options: []Option{
+				WithDecoder("application/json", panickingDecoder),
+				WithDecodeTimeout(time.Second),
+			},
+			expectedError: "recovered from panic in decoder: boom",
+		},
+		{
+			name:          "minimum viable resume check",
+			options:       []Option{WithMinimumViableResume(func(resume *Resume) bool { panic("boom") })},
+			expectedError: "recovered from panic in minimum viable resume check: boom",
+		},
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+	}))
+	defer svr.Close()
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs []string
+			logPrintf = func(format string, v ...any) {
+				logs = append(logs, fmt.Sprintf(format, v...))
+			}
+			var recovered []any
+			options := append(tc.options, WithHookPanicHandler(func(p any) {
+				recovered = append(recovered, p)
+			}))
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.ErrorContains(t, err, tc.expectedError)
+			require.NotErrorIs(t, err, ErrUnusableResume)
+			require.Equal(t, []string{"rps: " + tc.expectedError}, logs)
+			require.Equal(t, []any{"boom"}, recovered)
+		})
+	}
+}
+
+func TestEstimateCostWithPanickingPricingModel(t *testing.T) {
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	var logs []string
+	logPrintf = func(format string, v ...any) {
+		logs = append(logs, fmt.Sprintf(format, v...))
+	}
+	client := NewResumeParsingServiceClient("TOKEN", "URL", WithPricingModel(func(bytes, pages int) float64 {
+		panic("boom")
+	}))
+	cost := client.EstimateCost([]byte("document"))
+	require.Equal(t, Cost{Bytes: 8, Pages: 1}, cost)
+	require.Equal(t, []string{"rps: recovered from panic in pricing model: boom"}, logs)
+}
@@ -0,0 +1,73 @@
+package rps
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+// maxInflatedStreamSize bounds the size of each PDF stream
+// inflated while looking for a text layer.
+const maxInflatedStreamSize = 1 << 20
+
+var (
+	pdfMagic          = []byte("%PDF-")
+	pdfFontMarker     = []byte("/Font")
+	pdfStreamStart    = []byte("stream")
+	pdfStreamEnd      = []byte("endstream")
+	pdfTextOperatorTj = []byte("Tj")
+	pdfTextOperatorTJ = []byte("TJ")
+)
+
+// isPDF checks whether the file contents are a PDF document.
+func isPDF(fileContents []byte) bool {
+	return bytes.HasPrefix(fileContents, pdfMagic)
+}
+
+// hasTextMarkers checks whether b contains a font resource or
+// a text-showing operator, both of which imply a text layer.
+func hasTextMarkers(b []byte) bool {
+	return bytes.Contains(b, pdfFontMarker) ||
+		bytes.Contains(b, pdfTextOperatorTj) ||
+		bytes.Contains(b, pdfTextOperatorTJ)
+}
+
+// inflatedStreams returns the contents of the zlib compressed
+// (FlateDecode) streams of a PDF document.
+func inflatedStreams(fileContents []byte) [][]byte {
+	var streams [][]byte
+	for rest := fileContents; ; {
+		start := bytes.Index(rest, pdfStreamStart)
+		if start < 0 {
+			return streams
+		}
+		rest = bytes.TrimLeft(rest[start+len(pdfStreamStart):], "\r\n")
+		end := bytes.Index(rest, pdfStreamEnd)
+		if end < 0 {
+			return streams
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(rest[:end]))
+		if err == nil {
+			inflated, _ := io.ReadAll(io.LimitReader(reader, maxInflatedStreamSize))
+			streams = append(streams, inflated)
+		}
+		rest = rest[end+len(pdfStreamEnd):]
+	}
+}
+
+// looksLikeScannedPDF is a best-effort check of whether the file
+// contents are a PDF document without an extractable text layer,
+// i.e. neither its objects nor its compressed streams reference a
+// font or use text-showing operators. Such documents, usually
+// scanned images, produce near-empty parses unless OCR is used.
+func looksLikeScannedPDF(fileContents []byte) bool {
+	if !isPDF(fileContents) || hasTextMarkers(fileContents) {
+		return false
+	}
+	for _, stream := range inflatedStreams(fileContents) {
+		if hasTextMarkers(stream) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package rps
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readFixture reads a file from the testdata directory.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf(`error when reading fixture "%s": %v`, name, err)
+	}
+	return b
+}
+
+// compressedTextPDF returns a PDF whose only text markers
+// are inside a compressed object stream.
+func compressedTextPDF(t *testing.T) []byte {
+	t.Helper()
+	var stream bytes.Buffer
+	w := zlib.NewWriter(&stream)
+	_, _ = w.Write([]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"))
+	require.Nil(t, w.Close())
+	pdf := []byte("%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /Filter /FlateDecode >>\nstream\n")
+	pdf = append(pdf, stream.Bytes()...)
+	return append(pdf, []byte("\nendstream\nendobj\n%%EOF\n")...)
+}
+
+func TestLooksLikeScannedPDF(t *testing.T) {
+	testCases := []struct {
+		name           string
+		fileContents   []byte
+		expectedOutput bool
+	}{
+		{
+			name:         "text pdf",
+			fileContents: readFixture(t, "text.pdf"),
+		},
+		{
+			name:           "image-only pdf",
+			fileContents:   readFixture(t, "scanned.pdf"),
+			expectedOutput: true,
+		},
+		{
+			name:         "text markers inside a compressed object stream",
+			fileContents: compressedTextPDF(t),
+		},
+		{
+			name:         "not a pdf",
+			fileContents: []byte("PK\x03\x04"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, looksLikeScannedPDF(tc.fileContents))
+		})
+	}
+}
+
+func TestParseDocumentWithScannedPDFDetection(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		fixture        string
+		expectedOutput bool
+	}{
+		{
+			name:    "detection is disabled by default",
+			fixture: "scanned.pdf",
+		},
+		{
+			name:    "text pdf",
+			options: []Option{WithScannedPDFDetection()},
+			fixture: "text.pdf",
+		},
+		{
+			name:           "image-only pdf",
+			options:        []Option{WithScannedPDFDetection()},
+			fixture:        "scanned.pdf",
+			expectedOutput: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			var md CallMetadata
+			_, err := client.ParseDocument(context.TODO(), readFixture(t, tc.fixture), WithCallMetadata(&md))
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, md.LikelyScannedPDF)
+		})
+	}
+}
+
+func TestParseDocumentWithOCR(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		fixture        string
+		expectedOutput any
+	}{
+		{
+			name:    "flag is not sent by default",
+			fixture: "scanned.pdf",
+		},
+		{
+			name:           "enabled",
+			options:        []Option{WithOCR(true)},
+			fixture:        "text.pdf",
+			expectedOutput: true,
+		},
+		{
+			name:           "disabled",
+			options:        []Option{WithOCR(false)},
+			fixture:        "scanned.pdf",
+			expectedOutput: false,
+		},
+		{
+			name:           "enabled along with detection, text pdf",
+			options:        []Option{WithOCR(true), WithScannedPDFDetection()},
+			fixture:        "text.pdf",
+			expectedOutput: false,
+		},
+		{
+			name:           "enabled along with detection, image-only pdf",
+			options:        []Option{WithOCR(true), WithScannedPDFDetection()},
+			fixture:        "scanned.pdf",
+			expectedOutput: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			_, err := client.ParseDocument(context.TODO(), readFixture(t, tc.fixture))
+			require.Nil(t, err)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+			var body map[string]any
+			require.Nil(t, json.NewDecoder(mock.Req.Body).Decode(&body))
+			require.Equal(t, tc.expectedOutput, body["ocr"])
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package rps
+
+import (
+	"strings"
+	"unicode"
+)
+
+// regionCallingCodes maps ISO 3166-1 alpha-2 region codes
+// to their international calling codes.
+var regionCallingCodes = map[string]string{
+	"AR": "+54",
+	"AT": "+43",
+	"AU": "+61",
+	"BE": "+32",
+	"BR": "+55",
+	"CA": "+1",
+	"CH": "+41",
+	"CL": "+56",
+	"CN": "+86",
+	"CO": "+57",
+	"DE": "+49",
+	"DK": "+45",
+	"ES": "+34",
+	"FI": "+358",
+	"FR": "+33",
+	"GB": "+44",
+	"IE": "+353",
+	"IL": "+972",
+	"IN": "+91",
+	"IT": "+39",
+	"JP": "+81",
+	"KR": "+82",
+	"MX": "+52",
+	"NL": "+31",
+	"NO": "+47",
+	"NZ": "+64",
+	"PL": "+48",
+	"PT": "+351",
+	"SE": "+46",
+	"SG": "+65",
+	"UA": "+380",
+	"US": "+1",
+	"ZA": "+27",
+}
+
+// regionTrunkPrefixes maps the region codes of regionCallingCodes to
+// the trunk prefix dialed before national numbers within the region,
+// and dropped from their international format, e.g. "020 7946 0000" in
+// GB is +44 20 7946 0000. Regions without a trunk prefix are omitted.
+var regionTrunkPrefixes = map[string]string{
+	"AR": "0",
+	"AT": "0",
+	"AU": "0",
+	"BE": "0",
+	"BR": "0",
+	"CA": "1",
+	"CH": "0",
+	"CN": "0",
+	"DE": "0",
+	"FI": "0",
+	"FR": "0",
+	"GB": "0",
+	"IE": "0",
+	"IL": "0",
+	"IN": "0",
+	"JP": "0",
+	"KR": "0",
+	"NL": "0",
+	"NZ": "0",
+	"SE": "0",
+	"UA": "0",
+	"US": "1",
+	"ZA": "0",
+}
+
+// digits returns only the digits contained in s.
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// E164 returns the phone number in E.164 format (e.g. "+12677210053").
+// Without a country code, the calling code of the region set through
+// WithPhoneDefaultRegion, if any, is used, dropping the trunk prefix of
+// the national number. It returns an empty string when either the
+// country code or the national number is missing.
+func (p PhoneNumber) E164() string {
+	countryCode := digits(p.CountryCode)
+	nationalNumber := digits(p.NationalNumber)
+	if countryCode == "" && nationalNumber != "" {
+		countryCode, nationalNumber = inRegion(nationalNumber, p.defaultRegion)
+	}
+	if countryCode == "" || nationalNumber == "" {
+		return ""
+	}
+	return "+" + countryCode + nationalNumber
+}
+
+// inRegion returns the calling code of the region, without the plus
+// sign, and the national number without the trunk prefix of the region.
+// An empty calling code is returned for an unknown region.
+func inRegion(nationalNumber, region string) (string, string) {
+	region = strings.ToUpper(region)
+	callingCode, ok := regionCallingCodes[region]
+	if !ok {
+		return "", nationalNumber
+	}
+	return digits(callingCode), strings.TrimPrefix(nationalNumber, regionTrunkPrefixes[region])
+}
+
+// setDefaultRegion sets the region used by E164
+// for the phone numbers without a country code.
+func setDefaultRegion(phoneNumbers []PhoneNumber, region string) {
+	for i := range phoneNumbers {
+		phoneNumbers[i].defaultRegion = region
+	}
+}
@@ -0,0 +1,100 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE164(t *testing.T) {
+	testCases := []struct {
+		name           string
+		phoneNumber    PhoneNumber
+		expectedOutput string
+	}{
+		{
+			name:           "with country code",
+			phoneNumber:    PhoneNumber{CountryCode: "+1", CountryName: "US", NationalNumber: "(267) 721-0053"},
+			expectedOutput: "+12677210053",
+		},
+		{
+			name:        "without country code",
+			phoneNumber: PhoneNumber{NationalNumber: "(267) 721-0053"},
+		},
+		{
+			name:        "without national number",
+			phoneNumber: PhoneNumber{CountryCode: "+1"},
+		},
+		{
+			name:           "with default region",
+			phoneNumber:    PhoneNumber{NationalNumber: "(267) 721-0053", defaultRegion: "us"},
+			expectedOutput: "+12677210053",
+		},
+		{
+			name:           "with default region and trunk prefix",
+			phoneNumber:    PhoneNumber{NationalNumber: "020 7946 0000", defaultRegion: "GB"},
+			expectedOutput: "+442079460000",
+		},
+		{
+			name:           "with default region without trunk prefix",
+			phoneNumber:    PhoneNumber{NationalNumber: "045 802 7111", defaultRegion: "IT"},
+			expectedOutput: "+390458027111",
+		},
+		{
+			name:           "with country code and default region",
+			phoneNumber:    PhoneNumber{CountryCode: "+39", NationalNumber: "045 802 7111", defaultRegion: "GB"},
+			expectedOutput: "+390458027111",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, tc.phoneNumber.E164())
+		})
+	}
+}
+
+func TestParseDocumentWithPhoneDefaultRegion(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedOutput []string
+	}{
+		{
+			name:           "without default region",
+			expectedOutput: []string{"+390458027111", "", ""},
+		},
+		{
+			name:           "with default region",
+			options:        []Option{WithPhoneDefaultRegion("us")},
+			expectedOutput: []string{"+390458027111", "+12677210053", "+12677210053"},
+		},
+		{
+			name:           "with unknown default region",
+			options:        []Option{WithPhoneDefaultRegion("XX")},
+			expectedOutput: []string{"+390458027111", "", ""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				PhoneNumbers: []PhoneNumber{
+					{CountryCode: "+39", CountryName: "IT", NationalNumber: "045 802 7111"},
+					{NationalNumber: "(267) 721-0053"},
+					{NationalNumber: "1 (267) 721-0053"},
+				},
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			output := make([]string, 0, len(resume.PhoneNumbers))
+			for _, phoneNumber := range resume.PhoneNumbers {
+				output = append(output, phoneNumber.E164())
+			}
+			require.Equal(t, tc.expectedOutput, output)
+			// The decoded country codes are left untouched.
+			require.Equal(t, "+39", resume.PhoneNumbers[0].CountryCode)
+			require.Empty(t, resume.PhoneNumbers[1].CountryCode)
+			require.Empty(t, resume.PhoneNumbers[2].CountryCode)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package rps
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Normalizer modifies a decoded resume in place. Normalizers are
+// applied in order through WithNormalizers, and an error fails the parse.
+type Normalizer func(resume *Resume) error
+
+// DedupSkills merges the skills sharing a name, ignoring case and
+// surrounding whitespace, into the first one and keeps the highest
+// NumMonths.
+func DedupSkills(resume *Resume) error {
+	// Duplicates usually repeat the same experience, which is not added up.
+	resume.Skills = mergeSkills(resume.Skills, func(name string) string {
+		return strings.ToLower(strings.TrimSpace(name))
+	}, func(a, b int) int { return max(a, b) })
+	return nil
+}
+
+// TrimOrganizations trims the surrounding whitespace of the
+// organizations of the positions and educations.
+func TrimOrganizations(resume *Resume) error {
+	for i := range resume.Positions {
+		resume.Positions[i].Organization = strings.TrimSpace(resume.Positions[i].Organization)
+	}
+	for i := range resume.Educations {
+		resume.Educations[i].Organization = strings.TrimSpace(resume.Educations[i].Organization)
+	}
+	return nil
+}
+
+// NormalizeEmails lowercases, trims and dedupes the emails, preserving
+// the order of first occurrence, like WithEmailNormalization.
+func NormalizeEmails(resume *Resume) error {
+	resume.Emails = normalizeEmails(resume.Emails, false)
+	return nil
+}
+
+// applyNormalizers applies the normalizers in order,
+// stopping at the first error.
+func applyNormalizers(resume *Resume, normalizers []Normalizer) error {
+	for i, normalizer := range normalizers {
+		if err := normalizer(resume); err != nil {
+			return errors.Wrapf(err, "applying normalizer %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltInNormalizers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		normalizer     Normalizer
+		input          *Resume
+		expectedOutput *Resume
+	}{
+		{
+			name:       "dedup skills",
+			normalizer: DedupSkills,
+			input: &Resume{Skills: []Skill{
+				{Name: "Research", NumMonths: 12},
+				{Name: "Teamwork", NumMonths: 3},
+				{Name: " research ", NumMonths: 80},
+			}},
+			expectedOutput: &Resume{Skills: []Skill{
+				{Name: "Research", NumMonths: 80},
+				{Name: "Teamwork", NumMonths: 3},
+			}},
+		},
+		{
+			name:       "trim organizations",
+			normalizer: TrimOrganizations,
+			input: &Resume{
+				Positions:  []Position{{Organization: " Camelot\n"}},
+				Educations: []Education{{Organization: "\tAvalon "}},
+			},
+			expectedOutput: &Resume{
+				Positions:  []Position{{Organization: "Camelot"}},
+				Educations: []Education{{Organization: "Avalon"}},
+			},
+		},
+		{
+			name:           "normalize emails",
+			normalizer:     NormalizeEmails,
+			input:          &Resume{Emails: []string{" Morgana@Camelot.com", "morgana@camelot.com", ""}},
+			expectedOutput: &Resume{Emails: []string{"morgana@camelot.com"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Nil(t, tc.normalizer(tc.input))
+			require.Equal(t, tc.expectedOutput, tc.input)
+		})
+	}
+}
+
+func TestParseDocumentWithNormalizers(t *testing.T) {
+	appendToSummary := func(s string) Normalizer {
+		return func(resume *Resume) error {
+			resume.Summary += s
+			return nil
+		}
+	}
+	testCases := []struct {
+		name            string
+		options         []Option
+		expectedSummary string
+		expectedError   error
+	}{
+		{
+			name:            "normalizers are applied in order",
+			options:         []Option{WithNormalizers(appendToSummary("a"), appendToSummary("b"))},
+			expectedSummary: "summary:ab",
+		},
+		{
+			name: "normalizers are applied in order across options",
+			options: []Option{
+				WithNormalizers(appendToSummary("b")),
+				WithNormalizers(appendToSummary("a")),
+			},
+			expectedSummary: "summary:ba",
+		},
+		{
+			name: "normalizers are applied after the other normalization options",
+			options: []Option{
+				WithNormalizers(appendToSummary(" <b>a</b>")),
+				WithStripHTML(),
+			},
+			expectedSummary: "summary: <b>a</b>",
+		},
+		{
+			name: "error stops the pipeline",
+			options: []Option{WithNormalizers(
+				func(resume *Resume) error { return errors.New("failure") },
+				appendToSummary("a"),
+			)},
+			expectedError: errors.New("normalizing resume: applying normalizer 0: failure"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{Summary: "summary:"}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			require.Nil(t, tc.expectedError)
+			require.Equal(t, tc.expectedSummary, resume.Summary)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package rps
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestQueue releases requests at a steady rate, buffering
+// up to a given number of waiting requests.
+type requestQueue struct {
+	slots    chan struct{}
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newRequestQueue creates a queue holding up to size
+// requests and releasing ratePerSec of them per second.
+func newRequestQueue(size, ratePerSec int) *requestQueue {
+	return &requestQueue{
+		slots:    make(chan struct{}, size),
+		interval: time.Second / time.Duration(ratePerSec),
+	}
+}
+
+// schedule returns the time the next request is released at.
+func (q *requestQueue) schedule() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	at := time.Now()
+	if q.next.After(at) {
+		at = q.next
+	}
+	q.next = at.Add(q.interval)
+	return at
+}
+
+// wait blocks until the request is released, first waiting
+// for room in the queue when it is full. A nil queue releases
+// requests immediately.
+func (q *requestQueue) wait(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+	select {
+	case q.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-q.slots }()
+	timer := time.NewTimer(time.Until(q.schedule()))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
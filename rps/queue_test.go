@@ -0,0 +1,83 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithRequestQueue(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithRequestQueue(3, 20))
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			errs <- err
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		require.Nil(t, <-errs)
+	}
+	require.Len(t, arrivals, 3)
+	sort.Slice(arrivals, func(i, j int) bool {
+		return arrivals[i].Before(arrivals[j])
+	})
+	for i := 1; i < len(arrivals); i++ {
+		require.GreaterOrEqual(t, arrivals[i].Sub(arrivals[i-1]), 40*time.Millisecond)
+	}
+}
+
+func TestRequestQueueBackpressure(t *testing.T) {
+	q := newRequestQueue(1, 1)
+	require.Nil(t, q.wait(context.TODO()))
+
+	// The next request is released after a second,
+	// holding the only room in the queue meanwhile.
+	go func() {
+		_ = q.wait(context.TODO())
+	}()
+	require.Eventually(t, func() bool {
+		return len(q.slots) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := q.wait(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestParseDocumentWithRequestQueueAndCanceledContext(t *testing.T) {
+	client := newClientWithMock(t, nil, WithRequestQueue(1, 1))
+	mock, ok := client.httpClient.(*resumeHttpClientMock)
+	require.True(t, ok)
+	_, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	mock.Req = nil
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.ParseDocument(ctx, []byte{})
+	require.NotNil(t, err)
+	require.Equal(t, "waiting in request queue: context deadline exceeded", err.Error())
+	require.Nil(t, mock.Req)
+}
@@ -0,0 +1,20 @@
+package rps
+
+import "strings"
+
+// RawTextLines splits the raw text into lines, for search indexing.
+// Lines end with "\n", "\r\n" or "\r", and are trimmed of their
+// surrounding whitespace, dropping the empty ones. It returns nil
+// if the raw text has no line.
+func (r *Resume) RawTextLines() []string {
+	var lines []string
+	fields := strings.FieldsFunc(r.RawText, func(c rune) bool {
+		return c == '\n' || c == '\r'
+	})
+	for _, field := range fields {
+		if line := strings.TrimSpace(field); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,49 @@
+package rps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawTextLines(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rawText        string
+		expectedOutput []string
+	}{
+		{
+			name:           "unix line endings",
+			rawText:        "MORGANA FAVERO\nMD, PhD\nPhiladelphia",
+			expectedOutput: []string{"MORGANA FAVERO", "MD, PhD", "Philadelphia"},
+		},
+		{
+			name:           "mixed line endings",
+			rawText:        "MORGANA FAVERO\r\nMD, PhD\nPhiladelphia\rPA",
+			expectedOutput: []string{"MORGANA FAVERO", "MD, PhD", "Philadelphia", "PA"},
+		},
+		{
+			name:           "lines are trimmed and empty ones dropped",
+			rawText:        "  MORGANA FAVERO \t\r\n\r\n   \nMD, PhD  ",
+			expectedOutput: []string{"MORGANA FAVERO", "MD, PhD"},
+		},
+		{
+			name:           "trailing newlines",
+			rawText:        "MORGANA FAVERO\nMD, PhD\r\n\n",
+			expectedOutput: []string{"MORGANA FAVERO", "MD, PhD"},
+		},
+		{
+			name:    "blank raw text",
+			rawText: " \r\n\n",
+		},
+		{
+			name: "empty raw text",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resume := &Resume{RawText: tc.rawText}
+			require.Equal(t, tc.expectedOutput, resume.RawTextLines())
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package rps
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DocumentReadError is returned when reading the document failed while
+// streaming it to the service, as opposed to failing to marshal the
+// request or to perform it.
+type DocumentReadError struct {
+	Err error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *DocumentReadError) Error() string {
+	return "reading document: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DocumentReadError) Unwrap() error {
+	return e.Err
+}
+
+// errDocumentNotRewindable is returned when an attempt is retried while
+// the document is read from a reader that cannot seek back to its start.
+var errDocumentNotRewindable = errors.New("document reader cannot be read again for a retry")
+
+// documentReader tells the errors of the reader of the document
+// apart from the errors of writing the request body.
+type documentReader struct {
+	r io.Reader
+}
+
+func (d documentReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &DocumentReadError{Err: err}
+	}
+	return n, err
+}
+
+// documentBody streams the parse document request of a document read
+// from a reader, encoding the document on the fly, so that neither the
+// document nor its encoding is ever held in memory at once.
+type documentBody struct {
+	r        io.Reader
+	start    int64
+	encoding *base64.Encoding
+	ocr      *bool
+
+	mu      sync.Mutex
+	started bool
+	done    chan struct{}
+}
+
+// newDocumentBody returns the body of the parse document request of
+// the document read from r. When r can seek, retries read it again
+// from its current offset.
+func (r *resumeParsingServiceClient) newDocumentBody(reader io.Reader) *documentBody {
+	body := &documentBody{r: reader, encoding: r.base64Encoding, ocr: r.ocrFlag(false)}
+	if seeker, ok := reader.(io.Seeker); ok {
+		body.start, _ = seeker.Seek(0, io.SeekCurrent)
+	}
+	return body
+}
+
+// open returns a reader of the request body, which streams the document
+// from its start once read. Opening it reads nothing, since the HTTP
+// client opens the body to probe its length.
+func (b *documentBody) open() (io.ReadCloser, error) {
+	return &lazyBody{open: b.stream}, nil
+}
+
+// stream starts streaming the request body, rewinding the
+// document if it was already read by a previous attempt.
+func (b *documentBody) stream() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started {
+		seeker, ok := b.r.(io.Seeker)
+		if !ok {
+			return nil, &DocumentReadError{Err: errDocumentNotRewindable}
+		}
+		// Waits for the previous attempt to stop reading the document.
+		<-b.done
+		if _, err := seeker.Seek(b.start, io.SeekStart); err != nil {
+			return nil, &DocumentReadError{Err: err}
+		}
+	}
+	b.started = true
+	b.done = make(chan struct{})
+	pr, pw := io.Pipe()
+	go func() {
+		defer close(b.done)
+		pw.CloseWithError(b.writeTo(pw))
+	}()
+	return pr, nil
+}
+
+// writeTo writes the request body, encoding the document as it is read.
+func (b *documentBody) writeTo(w io.Writer) error {
+	if _, err := io.WriteString(w, `{"base64_data":"`); err != nil {
+		return err
+	}
+	encoder := base64.NewEncoder(b.encoding, w)
+	if _, err := io.Copy(encoder, documentReader{b.r}); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	suffix := `"}`
+	if b.ocr != nil {
+		suffix = fmt.Sprintf(`","ocr":%t}`, *b.ocr)
+	}
+	_, err := io.WriteString(w, suffix)
+	return err
+}
+
+// lazyBody opens the body on its first read.
+type lazyBody struct {
+	open func() (io.ReadCloser, error)
+	body io.ReadCloser
+	err  error
+}
+
+func (b *lazyBody) Read(p []byte) (int, error) {
+	if b.body == nil && b.err == nil {
+		b.body, b.err = b.open()
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.body.Read(p)
+}
+
+func (b *lazyBody) Close() error {
+	if b.body == nil {
+		return nil
+	}
+	return b.body.Close()
+}
+
+// newDocumentRequest creates a parse document request whose body
+// streams the document, with an unknown length, so that the HTTP
+// client streams it for each attempt rather than buffering it.
+func (r *resumeParsingServiceClient) newDocumentRequest(ctx context.Context, call *callOptions, reader io.Reader) (*http.Request, error) {
+	req, err := r.newRequest(ctx, call, "api/parse", nil)
+	if err != nil {
+		return nil, err
+	}
+	body := r.newDocumentBody(reader)
+	req.Body, _ = body.open()
+	req.GetBody = body.open
+	req.ContentLength = -1
+	return req, nil
+}
+
+// ParseDocumentFromReader sends a resume document read from reader for
+// parsing, streaming it through a base64 encoder into the request body,
+// so that large documents are never held in memory. Retries read the
+// document again, hence require reader to be an io.Seeker. Options
+// inspecting the whole document, such as the ETag cache, the request
+// validator or the scanned PDF detection, do not apply. A failure to
+// read the document is returned as a *DocumentReadError.
+func (r *resumeParsingServiceClient) ParseDocumentFromReader(ctx context.Context, reader io.Reader, options ...CallOption) (*Resume, error) {
+	if err := r.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer r.lifecycle.end()
+	call := r.newCallOptions(options)
+	start := time.Now()
+	var resume Resume
+	err := r.parseDocumentFromReader(ctx, reader, &resume, call)
+	r.observeLatency(time.Since(start), call.metadata.StatusCode)
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// parseDocumentFromReader sends a resume document read from reader for
+// parsing and decodes the response into target.
+func (r *resumeParsingServiceClient) parseDocumentFromReader(ctx context.Context, reader io.Reader, target any, call *callOptions) error {
+	if err := r.requestQueue.wait(ctx); err != nil {
+		return errors.Wrap(err, "waiting in request queue")
+	}
+	req, err := r.newDocumentRequest(ctx, call, reader)
+	if err != nil {
+		return err
+	}
+	return r.sendParseDocumentRequest(req, "", nil, target, call)
+}
@@ -0,0 +1,118 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns its contents, then fails.
+type failingReader struct {
+	contents io.Reader
+	err      error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.contents.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestParseDocumentFromReader(t *testing.T) {
+	fileContents := bytes.Repeat([]byte("%PDF-1.7 resume "), 1<<12)
+	errRead := errors.New("disk failure")
+	testCases := []struct {
+		name             string
+		options          []Option
+		reader           func() io.Reader
+		failedAttempts   int32
+		expectedAttempts int32
+		expectedOCR      *bool
+		expectedError    error
+	}{
+		{
+			name:             "document is streamed",
+			reader:           func() io.Reader { return bytes.NewReader(fileContents) },
+			expectedAttempts: 1,
+		},
+		{
+			name:             "OCR flag is sent",
+			options:          []Option{WithOCR(true)},
+			reader:           func() io.Reader { return bytes.NewReader(fileContents) },
+			expectedAttempts: 1,
+			expectedOCR:      func() *bool { ocr := true; return &ocr }(),
+		},
+		{
+			name:             "seekable document is read again for a retry",
+			options:          []Option{WithMaxRetries(1), WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil)},
+			reader:           func() io.Reader { return bytes.NewReader(fileContents) },
+			failedAttempts:   1,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "document that cannot seek is not retried",
+			options:          []Option{WithMaxRetries(1), WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil)},
+			reader:           func() io.Reader { return struct{ io.Reader }{bytes.NewReader(fileContents)} },
+			failedAttempts:   1,
+			expectedAttempts: 1,
+			expectedError:    &DocumentReadError{Err: errDocumentNotRewindable},
+		},
+		{
+			name:             "error reading the document",
+			reader:           func() io.Reader { return &failingReader{contents: bytes.NewReader(fileContents), err: errRead} },
+			expectedAttempts: 0,
+			expectedError:    &DocumentReadError{Err: errRead},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body parseDocumentRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					return
+				}
+				atomic.AddInt32(&attempts, 1)
+				require.Equal(t, int64(-1), r.ContentLength)
+				decoded, err := base64.StdEncoding.DecodeString(body.Base64Data)
+				require.Nil(t, err)
+				require.Equal(t, fileContents, decoded)
+				require.Equal(t, tc.expectedOCR, body.OCR)
+				w.Header().Set("Content-Type", "application/json")
+				if atomic.LoadInt32(&attempts) <= tc.failedAttempts {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			resume, err := client.ParseDocumentFromReader(context.TODO(), tc.reader())
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				var readErr *DocumentReadError
+				require.True(t, errors.As(err, &readErr))
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, "Morgana", resume.FirstName)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package rps
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// redactedValue replaces the values of personal information.
+const redactedValue = "[REDACTED]"
+
+// personalInformationKeys lists the keys of the response whose
+// values are personal information: emails, phone numbers, street
+// addresses and the raw text they were extracted from.
+var personalInformationKeys = map[string]bool{
+	"emails":          true,
+	"national_number": true,
+	"street":          true,
+	"formatted":       true,
+	"raw_text":        true,
+}
+
+// redactJSON masks the personal information of a JSON response,
+// keeping its structure for debugging purposes. Keys are sorted.
+func redactJSON(raw []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(v, false))
+}
+
+// redactValue walks a decoded JSON value, masking every
+// string under a personal information key.
+func redactValue(v any, redact bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = redactValue(value, redact || personalInformationKeys[key])
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value, redact)
+		}
+	case string:
+		if redact && v != "" {
+			return redactedValue
+		}
+	}
+	return v
+}
@@ -0,0 +1,75 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactJSON(t *testing.T) {
+	testCases := []struct {
+		name          string
+		raw           string
+		expectedJSON  string
+		expectedError bool
+	}{
+		{
+			name: "personal information is masked",
+			raw: `{
+				"first_name": "Morgana",
+				"emails": ["morgana@avalon.org", ""],
+				"phone_numbers": [{"country_code": "1", "national_number": "2677210053"}],
+				"location": {"formatted": "1 Lake St, Avalon", "street": "1 Lake St", "city": "Avalon"},
+				"skills": [{"name": "Sorcery", "num_months": 120}],
+				"raw_text": "Morgana, 1 Lake St, morgana@avalon.org"
+			}`,
+			expectedJSON: `{
+				"first_name": "Morgana",
+				"emails": ["[REDACTED]", ""],
+				"phone_numbers": [{"country_code": "1", "national_number": "[REDACTED]"}],
+				"location": {"formatted": "[REDACTED]", "street": "[REDACTED]", "city": "Avalon"},
+				"skills": [{"name": "Sorcery", "num_months": 120}],
+				"raw_text": "[REDACTED]"
+			}`,
+		},
+		{
+			name:          "invalid JSON",
+			raw:           `Morgana`,
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redacted, err := redactJSON([]byte(tc.raw))
+			if tc.expectedError {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.JSONEq(t, tc.expectedJSON, string(redacted))
+		})
+	}
+}
+
+func TestParseDocumentWithLogFieldMasking(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana","emails":["morgana@avalon.org"]}`))
+	}))
+	defer svr.Close()
+	var logged []byte
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithLogFieldMasking(),
+		WithResponseHook(func(resume *Resume, raw []byte) {
+			logged = raw
+		}),
+	)
+	resume, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"first_name":"Morgana","emails":["[REDACTED]"]}`, string(logged))
+	require.NotContains(t, string(logged), "morgana@avalon.org")
+	require.Equal(t, []string{"morgana@avalon.org"}, resume.Emails)
+}
@@ -0,0 +1,109 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// replayClient is a client decoding a captured response
+// instead of calling the Resume Parsing Service.
+type replayClient struct {
+	path string
+}
+
+// NewReplayClient initializes a client whose calls ignore the documents and
+// decode the response captured in the given file, e.g. by
+// WithResponseCaptureDir, for offline testing and issue reproduction.
+func NewReplayClient(path string) ResumeParsingServiceClient {
+	return &replayClient{path: path}
+}
+
+// ParseDocument decodes the captured response, ignoring the document.
+func (c *replayClient) ParseDocument(ctx context.Context, fileContents []byte, options ...CallOption) (*Resume, error) {
+	var resume Resume
+	if err := c.ParseDocumentInto(ctx, fileContents, &resume, options...); err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// ParseDocumentInto decodes the captured response into
+// target, which must be a non-nil pointer.
+func (c *replayClient) ParseDocumentInto(ctx context.Context, fileContents []byte, target any, options ...CallOption) error {
+	if err := validateTarget(target); err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return errors.Wrap(err, "reading captured response")
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return errors.Wrap(err, "decoding captured response")
+	}
+	return nil
+}
+
+// ParseDocumentBundle decodes the captured response once per document.
+func (c *replayClient) ParseDocumentBundle(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error) {
+	resumes := make([]*Resume, 0, len(docs))
+	for _, doc := range docs {
+		resume, err := c.ParseDocument(ctx, doc, options...)
+		if err != nil {
+			return nil, err
+		}
+		resumes = append(resumes, resume)
+	}
+	return resumes, nil
+}
+
+// ParseDocuments decodes the captured response once per document.
+func (c *replayClient) ParseDocuments(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, []error) {
+	return parseDocuments(ctx, docs, nil, func(ctx context.Context, doc []byte) (*Resume, error) {
+		return c.ParseDocument(ctx, doc, options...)
+	})
+}
+
+// ParseDocumentsAgg decodes the captured response once per document,
+// aggregating the errors into a *MultiError.
+func (c *replayClient) ParseDocumentsAgg(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error) {
+	resumes, errs := c.ParseDocuments(ctx, docs, options...)
+	return resumes, aggregate(errs)
+}
+
+// ParseDocumentFromReader decodes the captured
+// response, ignoring the document.
+func (c *replayClient) ParseDocumentFromReader(ctx context.Context, reader io.Reader, options ...CallOption) (*Resume, error) {
+	return c.ParseDocument(ctx, nil, options...)
+}
+
+// ParseDocumentFromURL decodes the captured response,
+// ignoring the URL of the document.
+func (c *replayClient) ParseDocumentFromURL(ctx context.Context, fileURL string, options ...CallOption) (*Resume, error) {
+	return c.ParseDocument(ctx, nil, options...)
+}
+
+// ParseDocumentStreaming decodes the captured response,
+// ignoring the document, without partial updates.
+func (c *replayClient) ParseDocumentStreaming(ctx context.Context, fileContents []byte, onEvent func(PartialResume), options ...CallOption) (*Resume, error) {
+	return c.ParseDocument(ctx, fileContents, options...)
+}
+
+// EstimateCost estimates the cost of parsing the
+// document with the default pricing model.
+func (c *replayClient) EstimateCost(fileContents []byte) Cost {
+	return estimateCost(fileContents, defaultPricingModel)
+}
+
+// Warmup does nothing, as there is no connection to open.
+func (c *replayClient) Warmup(ctx context.Context, n int) error {
+	return nil
+}
+
+// Shutdown does nothing, as there is no in-flight request.
+func (c *replayClient) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayClient(t *testing.T) {
+	positionStart := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	educationStart := time.Date(2010, 9, 1, 0, 0, 0, 0, time.UTC)
+	educationEnd := time.Date(2014, 6, 1, 0, 0, 0, 0, time.UTC)
+	expectedResume := &Resume{
+		FirstName:    "Morgana",
+		LastName:     "Le Fay",
+		Emails:       []string{"[REDACTED]"},
+		Location:     Location{City: "Avalon", Formatted: "[REDACTED]", Street: "[REDACTED]"},
+		PhoneNumbers: []PhoneNumber{{CountryCode: "1", NationalNumber: "[REDACTED]"}},
+		Positions: []Position{
+			{Title: "Sorceress", Organization: "Camelot", StartDate: &positionStart, Confidence: 1},
+		},
+		Educations: []Education{
+			{Degree: "Bachelor of Arts", Organization: "University of Avalon", StartDate: &educationStart, EndDate: &educationEnd, Confidence: 0.9},
+		},
+		Skills: []Skill{{Name: "Sorcery", NumMonths: 120}},
+	}
+	testCases := []struct {
+		name           string
+		path           string
+		expectedOutput *Resume
+		expectedError  error
+	}{
+		{
+			name:           "captured response",
+			path:           "testdata/captured_response.json",
+			expectedOutput: expectedResume,
+		},
+		{
+			name:          "missing file",
+			path:          "testdata/missing.json",
+			expectedError: errors.New("reading captured response: open testdata/missing.json: no such file or directory"),
+		},
+		{
+			name:          "invalid response",
+			path:          "testdata/text.pdf",
+			expectedError: errors.New("decoding captured response: invalid character '%' looking for beginning of value"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewReplayClient(tc.path)
+			output, err := client.ParseDocument(context.TODO(), []byte("ignored"))
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestReplayClientBundle(t *testing.T) {
+	client := NewReplayClient("testdata/captured_response.json")
+	resumes, err := client.ParseDocumentBundle(context.TODO(), [][]byte{{}, {}})
+	require.Nil(t, err)
+	require.Len(t, resumes, 2)
+	require.Equal(t, resumes[0], resumes[1])
+	require.Equal(t, "Morgana", resumes[0].FirstName)
+}
+
+func TestReplayClientDocuments(t *testing.T) {
+	client := NewReplayClient("testdata/captured_response.json")
+	resumes, err := client.ParseDocumentsAgg(context.TODO(), [][]byte{{}, {}})
+	require.Nil(t, err)
+	require.Len(t, resumes, 2)
+	require.Equal(t, resumes[0], resumes[1])
+	require.Equal(t, "Morgana", resumes[0].FirstName)
+
+	client = NewReplayClient("testdata/missing.json")
+	resumes, err = client.ParseDocumentsAgg(context.TODO(), [][]byte{{}})
+	require.Equal(t, []*Resume{nil}, resumes)
+	var multiErr *MultiError
+	require.ErrorAs(t, err, &multiErr)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,255 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// StatusCodeRange returns the status codes from `from` to `to`,
+// both inclusive, e.g. StatusCodeRange(500, 599) for all 5xx.
+func StatusCodeRange(from, to int) []int {
+	statusCodes := make([]int, 0, to-from+1)
+	for statusCode := from; statusCode <= to; statusCode++ {
+		statusCodes = append(statusCodes, statusCode)
+	}
+	return statusCodes
+}
+
+// statusCodeSet builds a set from the given status codes.
+func statusCodeSet(statusCodes []int) map[int]bool {
+	set := make(map[int]bool, len(statusCodes))
+	for _, statusCode := range statusCodes {
+		set[statusCode] = true
+	}
+	return set
+}
+
+// statusCodesRetryPolicy returns a retry policy that retries responses
+// whose status code is in `retry` but not in `noRetry`. Requests that
+// failed without a response are not retried.
+func statusCodesRetryPolicy(retry, noRetry []int) checkRetryPolicy {
+	retrySet := statusCodeSet(retry)
+	noRetrySet := statusCodeSet(noRetry)
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if resp == nil {
+			return false, err
+		}
+		return retrySet[resp.StatusCode] && !noRetrySet[resp.StatusCode], err
+	}
+}
+
+// retryCountsKey is the context key of the retry counts of a request.
+type retryCountsKey struct{}
+
+// retryCounts counts the retries of a request per status code.
+type retryCounts struct {
+	mu     sync.Mutex
+	counts map[int]int
+}
+
+// spend counts a retry of the given status code, reporting
+// whether the budget of the status code allows it.
+func (c *retryCounts) spend(statusCode, budget int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts[statusCode] >= budget {
+		return false
+	}
+	c.counts[statusCode]++
+	return true
+}
+
+// timeoutRetriesKey is the context key of the timeout retry counts of a request.
+type timeoutRetriesKey struct{}
+
+// timeoutRetryCounts counts the retries of a request after
+// a timeout apart from the other retries.
+type timeoutRetryCounts struct {
+	mu       sync.Mutex
+	timeouts int
+	others   int
+}
+
+// spend counts a retry, reporting whether the
+// maximum of retries of its kind allows it.
+func (c *timeoutRetryCounts) spend(timeout bool, maxTimeouts, maxOthers int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count, max := &c.others, maxOthers
+	if timeout {
+		count, max = &c.timeouts, maxTimeouts
+	}
+	if *count >= max {
+		return false
+	}
+	*count++
+	return true
+}
+
+// withRetryCounts returns a context tracking the retries of a request
+// when a retry budget is set through WithRetryBudget, or timeouts are
+// retried through WithRetryOnTimeout.
+func (r *resumeParsingServiceClient) withRetryCounts(ctx context.Context) context.Context {
+	if r.retryBudget != nil {
+		ctx = context.WithValue(ctx, retryCountsKey{}, &retryCounts{counts: make(map[int]int)})
+	}
+	if r.timeoutRetries > 0 {
+		ctx = context.WithValue(ctx, timeoutRetriesKey{}, &timeoutRetryCounts{})
+	}
+	return ctx
+}
+
+// totalRetryBudget returns the sum of the retry budgets.
+func totalRetryBudget(budget map[int]int) int {
+	total := 0
+	for _, n := range budget {
+		total += n
+	}
+	return total
+}
+
+// retryBudgetPolicy returns a retry policy that retries each status
+// code up to its own budget, counting the retries of each request
+// through its context. Requests that failed without a response, or
+// whose status code has no budget, are not retried.
+func retryBudgetPolicy(budget map[int]int) checkRetryPolicy {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		counts, ok := ctx.Value(retryCountsKey{}).(*retryCounts)
+		if resp == nil || !ok {
+			return false, err
+		}
+		return counts.spend(resp.StatusCode, budget[resp.StatusCode]), err
+	}
+}
+
+// isTimeout reports whether the attempt failed because it timed out,
+// either exceeding its deadline or through a network timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// timeoutRetryPolicy returns a retry policy that retries the attempts
+// that timed out up to maxTimeouts times, as long as the context of the
+// request is not done, and defers to the given policy otherwise, whose
+// retries are bounded by maxOthers.
+func timeoutRetryPolicy(maxTimeouts, maxOthers int, policy checkRetryPolicy) checkRetryPolicy {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		counts, ok := ctx.Value(timeoutRetriesKey{}).(*timeoutRetryCounts)
+		if ok && ctx.Err() == nil && isTimeout(err) {
+			return counts.spend(true, maxTimeouts, maxOthers), err
+		}
+		if policy == nil {
+			return false, err
+		}
+		retry, checkErr := policy(ctx, resp, err)
+		if retry && ok && !counts.spend(false, maxTimeouts, maxOthers) {
+			return false, checkErr
+		}
+		return retry, checkErr
+	}
+}
+
+// isEmptyJSON reports whether the body is empty or only holds
+// zero values: null, false, 0, "" or empty arrays and objects,
+// possibly nested. A body that is not JSON is not empty.
+func isEmptyJSON(body []byte) bool {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return true
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return false
+	}
+	return isZeroJSONValue(value)
+}
+
+// isZeroJSONValue reports whether a decoded JSON value is a zero value.
+func isZeroJSONValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	case []any:
+		for _, item := range v {
+			if !isZeroJSONValue(item) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		for _, item := range v {
+			if !isZeroJSONValue(item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// maxEmptyBodySize is the size beyond which a body is not inspected
+// by emptyBodyRetryPolicy, as it cannot be empty anymore: even a resume
+// whose every field is a zero value is much smaller.
+const maxEmptyBodySize = 64 << 10
+
+// prefixedBody is a response body whose beginning was read ahead.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// emptyBodyRetryPolicy returns a retry policy that retries 200 responses
+// whose body is empty, and defers to the given policy otherwise. Up to
+// maxEmptyBodySize bytes of the body are buffered to be inspected, then
+// restored for decoding. HEAD responses never have a body, hence they are
+// left to the policy.
+func emptyBodyRetryPolicy(policy checkRetryPolicy) checkRetryPolicy {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err == nil && resp != nil && resp.StatusCode == http.StatusOK && resp.Request.Method != http.MethodHead {
+			body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxEmptyBodySize+1))
+			if readErr != nil {
+				resp.Body.Close()
+				return true, readErr
+			}
+			if len(body) > maxEmptyBodySize {
+				resp.Body = prefixedBody{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+			} else {
+				resp.Body.Close()
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				if isEmptyJSON(body) {
+					return true, nil
+				}
+			}
+		}
+		if policy == nil {
+			return false, err
+		}
+		return policy(ctx, resp, err)
+	}
+}
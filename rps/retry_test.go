@@ -0,0 +1,434 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCodesRetryPolicy(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	policy := statusCodesRetryPolicy(StatusCodeRange(500, 599), []int{http.StatusNotImplemented})
+	testCases := []struct {
+		name          string
+		ctx           context.Context
+		resp          *http.Response
+		err           error
+		expectedRetry bool
+		expectedError error
+	}{
+		{
+			name:          "retryable status code",
+			ctx:           context.Background(),
+			resp:          &http.Response{StatusCode: http.StatusBadGateway},
+			expectedRetry: true,
+		},
+		{
+			name: "non-retryable status code takes precedence",
+			ctx:  context.Background(),
+			resp: &http.Response{StatusCode: http.StatusNotImplemented},
+		},
+		{
+			name: "status code not listed",
+			ctx:  context.Background(),
+			resp: &http.Response{StatusCode: http.StatusTooManyRequests},
+		},
+		{
+			name:          "no response",
+			ctx:           context.Background(),
+			err:           errors.New("random error"),
+			expectedError: errors.New("random error"),
+		},
+		{
+			name:          "cancelled context",
+			ctx:           cancelledCtx,
+			resp:          &http.Response{StatusCode: http.StatusBadGateway},
+			expectedError: context.Canceled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retry, err := policy(tc.ctx, tc.resp, tc.err)
+			require.Equal(t, tc.expectedRetry, retry)
+			require.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestParseDocumentWithRetryableStatusCodes(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		expectedAttempts int32
+	}{
+		{
+			name:             "retried",
+			statusCode:       http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "not retried",
+			statusCode:       http.StatusNotImplemented,
+			expectedAttempts: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithMaxRetries(2),
+				WithRetryableStatusCodes(StatusCodeRange(500, 599), []int{http.StatusNotImplemented}),
+			)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.NotNil(t, err)
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestParseDocumentWithRetryBudget(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCodes      []int
+		expectedAttempts int32
+	}{
+		{
+			name:             "429 respects its own budget",
+			statusCodes:      []int{http.StatusTooManyRequests},
+			expectedAttempts: 6,
+		},
+		{
+			name:             "503 respects its own budget",
+			statusCodes:      []int{http.StatusServiceUnavailable},
+			expectedAttempts: 3,
+		},
+		{
+			name: "budgets are tracked separately",
+			statusCodes: []int{
+				http.StatusServiceUnavailable,
+				http.StatusTooManyRequests,
+				http.StatusServiceUnavailable,
+				http.StatusTooManyRequests,
+				http.StatusServiceUnavailable,
+			},
+			expectedAttempts: 5,
+		},
+		{
+			name:             "status codes without a budget are not retried",
+			statusCodes:      []int{http.StatusInternalServerError},
+			expectedAttempts: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tc.statusCodes[int(attempt-1)%len(tc.statusCodes)])
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithRetryBudget(map[int]int{
+					http.StatusTooManyRequests:    5,
+					http.StatusServiceUnavailable: 2,
+				}),
+			)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.NotNil(t, err)
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+
+			// The budgets are per request.
+			atomic.StoreInt32(&attempts, 0)
+			_, err = client.ParseDocument(context.TODO(), []byte{})
+			require.NotNil(t, err)
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestParseDocumentWithPerAttemptTimeout(t *testing.T) {
+	testCases := []struct {
+		name             string
+		hangingAttempts  int32
+		expectedAttempts int32
+		expectedTimeout  bool
+	}{
+		{
+			name:             "hanging attempt is retried",
+			hangingAttempts:  1,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "every attempt hangs",
+			hangingAttempts:  3,
+			expectedAttempts: 3,
+			expectedTimeout:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The closing of the connection is only noticed
+				// once the request body was read.
+				_, _ = io.ReadAll(r.Body)
+				if atomic.AddInt32(&attempts, 1) <= tc.hangingAttempts {
+					<-r.Context().Done()
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+				WithMaxRetries(2),
+				WithPerAttemptTimeout(50*time.Millisecond),
+			)
+			ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+			defer cancel()
+			resume, err := client.ParseDocument(ctx, []byte{})
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+			if tc.expectedTimeout {
+				require.True(t, IsTimeout(err))
+				require.ErrorIs(t, err, ErrClientTimeout)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Morgana", resume.FirstName)
+		})
+	}
+}
+
+func TestParseDocumentWithRetryOnTimeout(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		hangingAttempts  int32
+		statusCode       int
+		expectedAttempts int32
+		expectedTimeout  bool
+	}{
+		{
+			name:             "timed out attempt is retried",
+			hangingAttempts:  1,
+			statusCode:       http.StatusOK,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "timeout retries are bounded",
+			hangingAttempts:  3,
+			statusCode:       http.StatusOK,
+			expectedAttempts: 2,
+			expectedTimeout:  true,
+		},
+		{
+			name:             "timeout retries are bounded apart from the other retries",
+			options:          []Option{WithMaxRetries(2)},
+			hangingAttempts:  3,
+			statusCode:       http.StatusOK,
+			expectedAttempts: 2,
+			expectedTimeout:  true,
+		},
+		{
+			name: "status retries are separate",
+			options: []Option{
+				WithMaxRetries(1),
+				WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil),
+			},
+			hangingAttempts:  1,
+			statusCode:       http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The closing of the connection is only noticed
+				// once the request body was read.
+				_, _ = io.ReadAll(r.Body)
+				if atomic.AddInt32(&attempts, 1) <= tc.hangingAttempts {
+					<-r.Context().Done()
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+			}))
+			defer svr.Close()
+			options := append([]Option{
+				WithPerAttemptTimeout(50 * time.Millisecond),
+				WithRetryOnTimeout(1),
+			}, tc.options...)
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+			defer cancel()
+			resume, err := client.ParseDocument(ctx, []byte{})
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+			if tc.expectedTimeout {
+				require.True(t, IsTimeout(err))
+				return
+			}
+			if tc.statusCode != http.StatusOK {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Morgana", resume.FirstName)
+		})
+	}
+}
+
+func TestIsEmptyJSON(t *testing.T) {
+	testCases := []struct {
+		body          string
+		expectedEmpty bool
+	}{
+		{body: "", expectedEmpty: true},
+		{body: " \n", expectedEmpty: true},
+		{body: "null", expectedEmpty: true},
+		{body: "{}", expectedEmpty: true},
+		{body: `{"first_name":"","skills":[],"positions":null,"years":0,"valid":false}`, expectedEmpty: true},
+		{body: `{"data":{"first_name":""}}`, expectedEmpty: true},
+		{body: `{"first_name":"Morgana"}`, expectedEmpty: false},
+		{body: `{"skills":[{"name":"","num_months":3}]}`, expectedEmpty: false},
+		{body: `{"valid":true}`, expectedEmpty: false},
+		{body: "<html></html>", expectedEmpty: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.body, func(t *testing.T) {
+			require.Equal(t, tc.expectedEmpty, isEmptyJSON([]byte(tc.body)))
+		})
+	}
+}
+
+func TestParseDocumentWithRetryOnEmptyBody(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		bodies           []string
+		expectedAttempts int32
+		expectedName     string
+		expectedError    bool
+	}{
+		{
+			name:             "empty body is retried",
+			options:          []Option{WithRetryOnEmptyBody()},
+			bodies:           []string{"", `{"first_name":"Morgana"}`},
+			expectedAttempts: 2,
+			expectedName:     "Morgana",
+		},
+		{
+			name:             "all-zero fields are retried",
+			options:          []Option{WithRetryOnEmptyBody()},
+			bodies:           []string{`{"first_name":"","skills":[]}`, "{}", `{"first_name":"Morgana"}`},
+			expectedAttempts: 3,
+			expectedName:     "Morgana",
+		},
+		{
+			name:             "populated body is not retried",
+			options:          []Option{WithRetryOnEmptyBody()},
+			bodies:           []string{`{"first_name":"Morgana"}`},
+			expectedAttempts: 1,
+			expectedName:     "Morgana",
+		},
+		{
+			name:             "retries are bounded",
+			options:          []Option{WithRetryOnEmptyBody()},
+			bodies:           []string{"{}"},
+			expectedAttempts: 3,
+			expectedError:    true,
+		},
+		{
+			name:             "body larger than inspected is decoded whole",
+			options:          []Option{WithRetryOnEmptyBody()},
+			bodies:           []string{`{"summary":"` + strings.Repeat("x", maxEmptyBodySize) + `","first_name":"Morgana"}`},
+			expectedAttempts: 1,
+			expectedName:     "Morgana",
+		},
+		{
+			name:             "empty body is not retried by default",
+			bodies:           []string{"{}", `{"first_name":"Morgana"}`},
+			expectedAttempts: 1,
+		},
+		{
+			name:             "other responses are left to the retry policy",
+			options:          []Option{WithRetryOnEmptyBody(), WithRetryableStatusCodes([]int{http.StatusServiceUnavailable}, nil)},
+			bodies:           []string{"503", `{"first_name":"Morgana"}`},
+			expectedAttempts: 2,
+			expectedName:     "Morgana",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				body := tc.bodies[min(int(attempt), len(tc.bodies))-1]
+				if body == "503" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}))
+			defer svr.Close()
+			options := append([]Option{WithMaxRetries(2)}, tc.options...)
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+			if tc.expectedError {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedName, resume.FirstName)
+		})
+	}
+}
+
+func TestParseDocumentWithRetryReasonLog(t *testing.T) {
+	var attempts int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+		}
+	}))
+	defer svr.Close()
+	var attemptNumbers []int
+	var reasons []string
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithMaxRetries(2),
+		WithRetryableStatusCodes([]int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, nil),
+		WithRetryReasonLog(func(attempt int, reason string) {
+			attemptNumbers = append(attemptNumbers, attempt)
+			reasons = append(reasons, reason)
+		}),
+	)
+	resume, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.Equal(t, "Morgana", resume.FirstName)
+	require.Equal(t, []int{1, 2}, attemptNumbers)
+	require.Equal(t, []string{"status 429, Retry-After honored", "status 503"}, reasons)
+}
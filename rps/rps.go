@@ -6,7 +6,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/TalentInc/resume-parsing-service-client/httpclient"
@@ -16,20 +20,93 @@ import (
 
 // For ease of unit testing.
 // Declaring these functions as global variables
-// makes it easy to mock them.
+// makes it easy to mock them. They are only read by
+// the calls, so they must not be replaced while any
+// call is in flight.
 var (
 	jsonMarshal           = json.Marshal
 	newRequestWithContext = http.NewRequestWithContext
 	newHttpClient         = httpclient.New
+	logPrintf             = log.Printf
+)
+
+const (
+	// serviceVersionHeader is the header used to pin the
+	// schema version the service responds with.
+	serviceVersionHeader = "Accept-Version"
+
+	// deadlineHeader is the header used to pass the time left
+	// before the deadline of the caller, in milliseconds.
+	deadlineHeader = "X-Deadline"
 )
 
 type checkRetryPolicy retryablehttp.CheckRetry
 
+// HttpError is returned, wrapped, when the service responded with an
+// unsuccessful status code, or when the request failed. Callers reach
+// it through errors.As to switch on its StatusCode, e.g. 422 for
+// documents that cannot be parsed, or to read its Body:
+//
+//	var httpErr *rps.HttpError
+//	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnprocessableEntity {
+//		...
+//	}
+type HttpError = httpclient.HttpError
+
 // ResumeParsingServiceClient defines the interface for a client capable of sending
 // resume documents to the Resume Parsing Service and receiving parsed data in response.
+//
+// A client is safe for concurrent use by multiple goroutines, and is meant
+// to be created once and reused: its options are only read once it is
+// created, the state of each call is local to it, and the state shared
+// by the calls (e.g. the ETag cache, the request queue or the shutdown
+// state) is synchronized. The functions given as options, such as hooks,
+// normalizers or caches, may be called concurrently and must be safe for
+// concurrent use as well.
 type ResumeParsingServiceClient interface {
 	// ParseDocument sends a resume document for parsing and returns the parsed data.
-	ParseDocument(ctx context.Context, fileContents []byte) (*Resume, error)
+	ParseDocument(ctx context.Context, fileContents []byte, options ...CallOption) (*Resume, error)
+
+	// ParseDocumentInto sends a resume document for parsing and decodes
+	// the parsed data into target, which must be a non-nil pointer.
+	ParseDocumentInto(ctx context.Context, fileContents []byte, target any, options ...CallOption) error
+
+	// ParseDocumentFromReader sends a resume document read from reader for
+	// parsing, streaming it into the request body, and returns the parsed data.
+	ParseDocumentFromReader(ctx context.Context, reader io.Reader, options ...CallOption) (*Resume, error)
+
+	// ParseDocumentFromURL sends the URL of a hosted resume document for
+	// the service to fetch and parse, and returns the parsed data.
+	ParseDocumentFromURL(ctx context.Context, fileURL string, options ...CallOption) (*Resume, error)
+
+	// ParseDocumentBundle sends several resume documents for parsing in a single
+	// request and returns the parsed data in the same order.
+	ParseDocumentBundle(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error)
+
+	// ParseDocuments sends each document for parsing in its own request and
+	// returns the parsed data and errors in the same order. Failed indexes are nil.
+	ParseDocuments(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, []error)
+
+	// ParseDocumentsAgg is like ParseDocuments, but returns a *MultiError
+	// aggregating the errors if some of the documents failed.
+	ParseDocumentsAgg(ctx context.Context, docs [][]byte, options ...CallOption) ([]*Resume, error)
+
+	// ParseDocumentStreaming sends a resume document for parsing, passes the
+	// partial updates streamed by the service to onEvent, and returns the
+	// complete parsed data.
+	ParseDocumentStreaming(ctx context.Context, fileContents []byte, onEvent func(PartialResume), options ...CallOption) (*Resume, error)
+
+	// EstimateCost estimates the cost of parsing a
+	// document, without submitting it.
+	EstimateCost(fileContents []byte) Cost
+
+	// Warmup opens n connections to the service so that the
+	// connection pool is primed before real traffic.
+	Warmup(ctx context.Context, n int) error
+
+	// Shutdown stops accepting new calls and waits for the
+	// in-flight ones to complete or the context to be done.
+	Shutdown(ctx context.Context) error
 }
 
 // resumeParsingServiceClient implements ResumeParsingServiceClient interface.
@@ -37,15 +114,78 @@ type resumeParsingServiceClient struct {
 	rioParseToken   string
 	rioParseBaseUrl string
 
-	checkRetryPolicy    checkRetryPolicy
-	maxIdleConns        int
-	maxIdleConnsPerHost int
-	maxConnsPerHost     int
-	maxRetries          int
-	retryWaitMin        time.Duration
-	retryWaitMax        time.Duration
-	requestDumpLogger   func(dump []byte)
-	dumpRequestBody     bool
+	checkRetryPolicy     checkRetryPolicy
+	maxIdleConns         int
+	maxIdleConnsPerHost  int
+	maxConnsPerHost      int
+	maxRetries           int
+	retryWaitMin         time.Duration
+	retryWaitMax         time.Duration
+	requestDumpLogger    func(dump []byte)
+	dumpRequestBody      bool
+	gmailNormalization   bool
+	normalizers          []func(resume *Resume)
+	normalizerPipeline   []Normalizer
+	base64Encoding       *base64.Encoding
+	acceptContentType    string
+	decoders             map[string]Decoder
+	latencyHistogram     func(d time.Duration, status int, clientName string)
+	slowParseThreshold   time.Duration
+	onSlowParse          func(d time.Duration, clientName string)
+	responseHook         func(resume *Resume, raw []byte)
+	scannedPDFDetection  bool
+	ocr                  *bool
+	closeConnection      bool
+	serviceVersion       string
+	envelopeDataKey      string
+	contextTokenKey      any
+	bodyBufferLimit      int
+	responseSchemaSource []byte
+	responseSchema       *responseSchema
+	deadlinePropagation  bool
+	checksumVerification bool
+	requestQueue         *requestQueue
+	lifecycle            lifecycle
+	logFieldMasking      bool
+	responseCapture      *responseCapture
+	retryBudget          map[int]int
+	dnsCacheTTL          time.Duration
+	dualStackDial        bool
+	dualStackFallback    time.Duration
+	maxConnLifetime      time.Duration
+	perAttemptTimeout    time.Duration
+	retryOnEmptyBody     bool
+	batchProgress        func(done, total int)
+	compression          bool
+	treat404AsEmpty      bool
+	retryReasonLog       func(attempt int, reason string)
+	bodyReadIdleTimeout  time.Duration
+	httpsUpgrade         bool
+	decodeTimeout        time.Duration
+	clientName           string
+	pricingModel         PricingModel
+	unmarshalInterceptor func(raw []byte, resume *Resume) error
+	hookPanicHandler     func(p any)
+	httpMethod           string
+	httpMethodOverride   string
+	normalizeWorkers     int
+	etagCache            ETagCache
+	acceptedLanguages    map[string]bool
+	idleConnTimeout      time.Duration
+	decodeWorkers        int
+	perEndpointPools     bool
+	hostHealthTracking   bool
+	maxHostFailures      int
+	hostCooldown         time.Duration
+	fallbackBaseUrls     []string
+	requestValidator     func(fileContents []byte) error
+	timeoutRetries       int
+	requestTimeout       time.Duration
+	viableResume         func(resume *Resume) bool
+	modelVersion         int
+	dateLocale           string
+	userAgent            string
+	headers              map[string]string
 
 	httpClient httpclient.Client
 }
@@ -53,10 +193,29 @@ type resumeParsingServiceClient struct {
 // newResumeParsingServiceClient applies the options and returns a
 // new instance of a client for the Resume Parsing Service.
 func newResumeParsingServiceClient(options []Option) *resumeParsingServiceClient {
-	client := new(resumeParsingServiceClient)
+	client := &resumeParsingServiceClient{
+		base64Encoding: base64.StdEncoding,
+		// A byte order mark is virtually never desired,
+		// hence it is always stripped.
+		normalizers: []func(resume *Resume){stripByteOrderMark},
+	}
 	for _, option := range options {
 		option(client)
 	}
+	// Leaves room for every status code to spend its retry budget.
+	if total := totalRetryBudget(client.retryBudget); total > client.maxRetries {
+		client.maxRetries = total
+	}
+	// Wraps whichever policy was set, regardless of the option order.
+	if client.retryOnEmptyBody {
+		client.checkRetryPolicy = emptyBodyRetryPolicy(client.checkRetryPolicy)
+	}
+	// Timeouts are retried on top of the other retries.
+	if client.timeoutRetries > 0 {
+		client.checkRetryPolicy = timeoutRetryPolicy(client.timeoutRetries, client.maxRetries, client.checkRetryPolicy)
+		client.maxRetries += client.timeoutRetries
+	}
+	client.guardHooks()
 	return client
 }
 
@@ -65,7 +224,15 @@ func NewResumeParsingServiceClient(rioParseToken, rioParseBaseUrl string, option
 	client := newResumeParsingServiceClient(options)
 	client.rioParseToken = rioParseToken
 	client.rioParseBaseUrl = rioParseBaseUrl
-	httpClient := newHttpClient(
+	if client.httpsUpgrade {
+		client.rioParseBaseUrl = client.upgradeToHTTPS(rioParseBaseUrl)
+	}
+	client.httpMethod = client.validHTTPMethod(client.httpMethod)
+	client.httpMethodOverride = client.validHTTPMethod(client.httpMethodOverride)
+	client.modelVersion = client.validModelVersion(client.modelVersion)
+	client.headers = client.validHeaders(client.headers)
+	client.responseSchema = client.validResponseSchema(client.responseSchemaSource)
+	httpClientOptions := []httpclient.Option{
 		httpclient.WithMaxIdleConns(client.maxIdleConns),
 		httpclient.WithMaxIdleConnsPerHost(client.maxIdleConnsPerHost),
 		httpclient.WithMaxConnsPerHost(client.maxConnsPerHost),
@@ -74,32 +241,317 @@ func NewResumeParsingServiceClient(rioParseToken, rioParseBaseUrl string, option
 		httpclient.WithRetryWaitMax(client.retryWaitMax),
 		httpclient.WithCheckRetryPolicy(retryablehttp.CheckRetry(client.checkRetryPolicy)),
 		httpclient.WithRequestDumpLogger(client.requestDumpLogger, client.dumpRequestBody),
-	)
-	client.httpClient = httpClient
+		httpclient.WithDNSCache(client.dnsCacheTTL),
+		httpclient.WithMaxConnLifetime(client.maxConnLifetime),
+		httpclient.WithPerAttemptTimeout(client.perAttemptTimeout),
+		httpclient.WithRetryReasonLog(client.retryReasonLog),
+		httpclient.WithBodyReadIdleTimeout(client.bodyReadIdleTimeout),
+		httpclient.WithIdleConnTimeout(client.idleConnTimeout),
+		httpclient.WithRequestTimeout(client.requestTimeout),
+	}
+	if client.dualStackDial {
+		httpClientOptions = append(httpClientOptions, httpclient.WithDualStackDial(client.dualStackFallback))
+	}
+	if client.compression {
+		httpClientOptions = append(httpClientOptions, httpclient.WithCompressionNegotiation())
+	}
+	if client.perEndpointPools {
+		httpClientOptions = append(httpClientOptions, httpclient.WithPerHostPools())
+	}
+	if client.hostHealthTracking {
+		baseUrls := []string{client.rioParseBaseUrl}
+		for _, baseUrl := range client.fallbackBaseUrls {
+			if client.httpsUpgrade {
+				baseUrl = client.upgradeToHTTPS(baseUrl)
+			}
+			baseUrls = append(baseUrls, baseUrl)
+		}
+		httpClientOptions = append(httpClientOptions, httpclient.WithHostHealthTracking(client.maxHostFailures, client.hostCooldown, baseUrls...))
+	}
+	if client.timeoutRetries > 0 {
+		httpClientOptions = append(httpClientOptions, httpclient.WithTimeoutRetriesByPolicy())
+	}
+	if client.deadlinePropagation {
+		httpClientOptions = append(httpClientOptions, httpclient.WithRequestHook(setDeadlineHeader))
+	}
+	client.httpClient = newHttpClient(httpClientOptions...)
 	return client
 }
 
-func (r *resumeParsingServiceClient) ParseDocument(ctx context.Context, fileContents []byte) (*Resume, error) {
-	url := fmt.Sprintf("%s/%s", r.rioParseBaseUrl, "api/parse")
-	encodedFileContents := base64.StdEncoding.EncodeToString(fileContents)
-	parseDocumentRequest := &parseDocumentRequest{
-		Base64Data: encodedFileContents,
+// newRequest creates a JSON request to the given path of the
+// Resume Parsing Service.
+func (r *resumeParsingServiceClient) newRequest(ctx context.Context, call *callOptions, path string, body []byte) (*http.Request, error) {
+	url := fmt.Sprintf("%s/%s", call.baseUrl, path)
+	req, err := newRequestWithContext(r.withRetryCounts(ctx), r.requestMethod(), url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", jsonContentType)
+	req.Header.Set(tokenHeader, r.token(ctx))
+	r.setCustomHeaders(req)
+	if r.acceptContentType != "" {
+		req.Header.Set("Accept", r.acceptContentType)
+	}
+	if call.eventStream != nil {
+		req.Header.Set("Accept", eventStreamContentType)
+	}
+	if r.serviceVersion != "" {
+		req.Header.Set(serviceVersionHeader, r.serviceVersion)
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	if r.httpMethodOverride != "" {
+		req.Header.Set(methodOverrideHeader, r.httpMethodOverride)
+	}
+	// Sends "Connection: close" and closes the
+	// connection once the response is read.
+	req.Close = r.closeConnection
+	return req, nil
+}
+
+// setDeadlineHeader sets the time left before the deadline of the
+// request context, if any, so that the service can abort in sync. It
+// is called before each attempt, so that retries send the time left.
+func setDeadlineHeader(req *http.Request, attempt int) {
+	deadline, ok := req.Context().Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline).Milliseconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	req.Header.Set(deadlineHeader, strconv.FormatInt(remaining, 10))
+}
+
+// token returns the token found in the context under the key
+// set through WithContextToken, falling back to the static one.
+func (r *resumeParsingServiceClient) token(ctx context.Context) string {
+	if r.contextTokenKey == nil {
+		return r.rioParseToken
+	}
+	if token, ok := ctx.Value(r.contextTokenKey).(string); ok && token != "" {
+		return token
+	}
+	return r.rioParseToken
+}
+
+// newParseDocumentRequest returns the request payload of a document,
+// without its contents, which are only encoded once the size of the
+// request body is checked.
+func (r *resumeParsingServiceClient) newParseDocumentRequest(likelyScanned bool) *parseDocumentRequest {
+	return &parseDocumentRequest{OCR: r.ocrFlag(likelyScanned)}
+}
+
+// ocrFlag returns the OCR flag sent to the service, or nil if OCR
+// was not configured. When the scanned PDF detection is enabled,
+// OCR is only requested for documents that look scanned.
+func (r *resumeParsingServiceClient) ocrFlag(likelyScanned bool) *bool {
+	if r.ocr == nil {
+		return nil
+	}
+	enabled := *r.ocr && (likelyScanned || !r.scannedPDFDetection)
+	return &enabled
+}
+
+// marshalParseDocumentRequest encodes the document and
+// marshals the request body.
+func (r *resumeParsingServiceClient) marshalParseDocumentRequest(fileContents []byte, likelyScanned bool) ([]byte, error) {
+	if err := r.validateDocuments(fileContents); err != nil {
+		return nil, err
+	}
+	parseDocumentRequest := r.newParseDocumentRequest(likelyScanned)
+	if err := r.checkBodySize(parseDocumentRequest, fileContents); err != nil {
+		return nil, err
 	}
+	parseDocumentRequest.Base64Data = r.base64Encoding.EncodeToString(fileContents)
 	j, err := jsonMarshal(parseDocumentRequest)
 	if err != nil {
 		return nil, errors.Wrap(err, "marshalling parse document request")
 	}
-	req, err := newRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(j))
+	return j, nil
+}
+
+func (r *resumeParsingServiceClient) ParseDocument(ctx context.Context, fileContents []byte, options ...CallOption) (*Resume, error) {
+	var resume Resume
+	if err := r.ParseDocumentInto(ctx, fileContents, &resume, options...); err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// ParseDocumentInto sends a resume document for parsing and decodes the
+// response into target, which must be a non-nil pointer, e.g. to a struct
+// embedding Resume along with fields the library does not model yet.
+// Normalization options only apply when target is a *Resume.
+func (r *resumeParsingServiceClient) ParseDocumentInto(ctx context.Context, fileContents []byte, target any, options ...CallOption) error {
+	if err := validateTarget(target); err != nil {
+		return err
+	}
+	if err := r.lifecycle.begin(); err != nil {
+		return err
+	}
+	defer r.lifecycle.end()
+	call := r.newCallOptions(options)
+	start := time.Now()
+	err := r.parseDocument(ctx, fileContents, target, call)
+	r.observeLatency(time.Since(start), call.metadata.StatusCode)
+	return err
+}
+
+// validateTarget checks whether target is a non-nil pointer.
+func validateTarget(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	return nil
+}
+
+// parseDocument sends a resume document for parsing
+// and decodes the parsed data into target.
+func (r *resumeParsingServiceClient) parseDocument(ctx context.Context, fileContents []byte, target any, call *callOptions) error {
+	if err := r.requestQueue.wait(ctx); err != nil {
+		return errors.Wrap(err, "waiting in request queue")
+	}
+	if r.scannedPDFDetection {
+		call.metadata.LikelyScannedPDF = looksLikeScannedPDF(fileContents)
+	}
+	start := time.Now()
+	j, err := r.marshalParseDocumentRequest(fileContents, call.metadata.LikelyScannedPDF)
+	call.metadata.EncodeDuration = time.Since(start)
 	if err != nil {
-		return nil, errors.Wrap(err, "creating request")
+		return err
+	}
+	req, err := r.newRequest(ctx, call, "api/parse", j)
+	if err != nil {
+		return err
+	}
+	key, cached := r.cachedResponse(req, fileContents, call.metadata.LikelyScannedPDF)
+	return r.sendParseDocumentRequest(req, key, cached, target, call)
+}
+
+// sendParseDocumentRequest sends a parse document request and decodes
+// the response into target, post-processing it when it is a *Resume.
+// The response is stored in the ETag cache under key, unless empty.
+func (r *resumeParsingServiceClient) sendParseDocumentRequest(req *http.Request, key string, cached *ETagEntry, target any, call *callOptions) error {
+	decoded := r.decodeTargetFor(target)
+	dst, env := r.envelopeFor(r.localizingDates(r.modelFor(decoded), localizeDates))
+	raw, resp, err := r.sendRequestAndDecodeResponse(req, r.validating(dst), cached, call.decoders(), r.keepsRawResponse(call))
+	call.metadata.observeResponse(resp, err)
+	if err != nil && r.treatsAsEmpty(call.metadata.StatusCode) {
+		return nil
+	}
+	var readErr *DocumentReadError
+	if errors.As(err, &readErr) {
+		return readErr
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("token", r.rioParseToken)
-	var resume Resume
-	resp, err := r.httpClient.SendRequestAndUnmarshallJsonResponse(req, &resume)
 	if err != nil {
-		return nil, errors.Wrap(err, "performing request")
+		return classifyRequestError(errors.Wrap(err, "performing request"), call.metadata.StatusCode)
 	}
 	defer resp.Body.Close()
-	return &resume, nil
+	setDecodeTarget(target, decoded)
+	call.metadata.Meta = env.Meta()
+	call.metadata.SchemaVersion = r.schemaVersion(raw)
+	r.cacheResponse(key, resp, raw)
+	r.responseCapture.capture(raw)
+	if resume, ok := target.(*Resume); ok {
+		if err := r.interceptUnmarshal(raw, resume); err != nil {
+			return err
+		}
+		if err := r.checkDetectedLanguage(resume); err != nil {
+			return err
+		}
+		postProcess := func() error {
+			if err := r.normalize(resume); err != nil {
+				return err
+			}
+			if err := r.checkViable(resume); err != nil {
+				return err
+			}
+			r.callResponseHook(resume, raw)
+			return nil
+		}
+		if call.deferPostProcessing != nil {
+			call.deferPostProcessing(postProcess)
+			return nil
+		}
+		return postProcess()
+	}
+	return nil
+}
+
+// logPrefix returns the prefix of the logs of the client,
+// tagged with its name, if any.
+func (r *resumeParsingServiceClient) logPrefix() string {
+	if r.clientName == "" {
+		return "rps"
+	}
+	return fmt.Sprintf("rps[%s]", r.clientName)
+}
+
+// treatsAsEmpty reports whether a failed parse with the given status
+// code is treated as an empty parse, as set through WithTreat404AsEmpty.
+func (r *resumeParsingServiceClient) treatsAsEmpty(statusCode int) bool {
+	return r.treat404AsEmpty && statusCode == http.StatusNotFound
+}
+
+// interceptUnmarshal calls the unmarshal interceptor, if any,
+// with the raw response body and the decoded resume.
+func (r *resumeParsingServiceClient) interceptUnmarshal(raw []byte, resume *Resume) error {
+	if r.unmarshalInterceptor == nil {
+		return nil
+	}
+	return errors.Wrap(r.unmarshalInterceptor(raw, resume), "intercepting unmarshal")
+}
+
+// callResponseHook calls the response hook, if any. With
+// WithLogFieldMasking, the personal information of the raw body
+// is masked, and nil is passed if the body cannot be masked.
+func (r *resumeParsingServiceClient) callResponseHook(resume *Resume, raw []byte) {
+	if r.responseHook == nil {
+		return
+	}
+	if r.logFieldMasking {
+		raw, _ = redactJSON(raw)
+	}
+	r.responseHook(resume, raw)
+}
+
+// statusCode returns the status code of the response or, if there
+// is none, the one held by the error. Zero is returned when the
+// request failed without a response.
+func statusCode(resp *http.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var httpErr *httpclient.HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// observeLatency reports the latency of a call to the latency
+// histogram, if any, and to the slow parse hook if above its threshold.
+func (r *resumeParsingServiceClient) observeLatency(d time.Duration, statusCode int) {
+	if r.latencyHistogram != nil {
+		r.latencyHistogram(d, statusCode, r.clientName)
+	}
+	if r.onSlowParse != nil && d > r.slowParseThreshold {
+		r.onSlowParse(d, r.clientName)
+	}
+}
+
+// normalize applies the configured normalizers to the resume,
+// then the normalizers given through WithNormalizers.
+func (r *resumeParsingServiceClient) normalize(resume *Resume) error {
+	for _, normalizer := range r.normalizers {
+		normalizer(resume)
+	}
+	if err := applyNormalizers(resume, r.normalizerPipeline); err != nil {
+		return errors.Wrap(err, "normalizing resume")
+	}
+	return nil
 }
@@ -3,9 +3,12 @@ package rps
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -26,6 +29,16 @@ func TestNewResumeParsingServiceClient(t *testing.T) {
 		expectedRetryWaitMin        time.Duration
 		expectedRetryWaitMax        time.Duration
 		expectedDumpRequestBody     bool
+		expectedDNSCacheTTL         time.Duration
+		expectedDualStackDial       bool
+		expectedDualStackFallback   time.Duration
+		expectedMaxConnLifetime     time.Duration
+		expectedPerAttemptTimeout   time.Duration
+		expectedCompression         bool
+		expectedBodyReadIdleTimeout time.Duration
+		expectedIdleConnTimeout     time.Duration
+		expectedPerEndpointPools    bool
+		expectedRequestTimeout      time.Duration
 	}{
 		{
 			name:    "no options provided",
@@ -44,6 +57,15 @@ func TestNewResumeParsingServiceClient(t *testing.T) {
 				WithRetryWaitMin(1 * time.Second),
 				WithRetryWaitMax(1 * time.Second),
 				WithRequestDumpLogger(func(dump []byte) {}, true),
+				WithDNSCache(1 * time.Minute),
+				WithDualStackDial(100 * time.Millisecond),
+				WithMaxConnLifetime(1 * time.Hour),
+				WithPerAttemptTimeout(1 * time.Second),
+				WithCompressionNegotiation(),
+				WithBodyReadIdleTimeout(1 * time.Second),
+				WithIdleConnTimeout(90 * time.Second),
+				WithPerEndpointPools(),
+				WithRequestTimeout(1 * time.Minute),
 			},
 			checkRetryPolicy:            true,
 			checkRequestDumpLogger:      true,
@@ -54,6 +76,16 @@ func TestNewResumeParsingServiceClient(t *testing.T) {
 			expectedRetryWaitMin:        1 * time.Second,
 			expectedRetryWaitMax:        1 * time.Second,
 			expectedDumpRequestBody:     true,
+			expectedDNSCacheTTL:         1 * time.Minute,
+			expectedDualStackDial:       true,
+			expectedDualStackFallback:   100 * time.Millisecond,
+			expectedMaxConnLifetime:     1 * time.Hour,
+			expectedPerAttemptTimeout:   1 * time.Second,
+			expectedCompression:         true,
+			expectedBodyReadIdleTimeout: 1 * time.Second,
+			expectedIdleConnTimeout:     90 * time.Second,
+			expectedPerEndpointPools:    true,
+			expectedRequestTimeout:      1 * time.Minute,
 		},
 	}
 	for _, tc := range testCases {
@@ -68,6 +100,16 @@ func TestNewResumeParsingServiceClient(t *testing.T) {
 			require.Equal(t, tc.expectedRetryWaitMin, clientWrapper.retryWaitMin)
 			require.Equal(t, tc.expectedRetryWaitMax, clientWrapper.retryWaitMax)
 			require.Equal(t, tc.expectedDumpRequestBody, clientWrapper.dumpRequestBody)
+			require.Equal(t, tc.expectedDNSCacheTTL, clientWrapper.dnsCacheTTL)
+			require.Equal(t, tc.expectedDualStackDial, clientWrapper.dualStackDial)
+			require.Equal(t, tc.expectedDualStackFallback, clientWrapper.dualStackFallback)
+			require.Equal(t, tc.expectedMaxConnLifetime, clientWrapper.maxConnLifetime)
+			require.Equal(t, tc.expectedPerAttemptTimeout, clientWrapper.perAttemptTimeout)
+			require.Equal(t, tc.expectedCompression, clientWrapper.compression)
+			require.Equal(t, tc.expectedBodyReadIdleTimeout, clientWrapper.bodyReadIdleTimeout)
+			require.Equal(t, tc.expectedIdleConnTimeout, clientWrapper.idleConnTimeout)
+			require.Equal(t, tc.expectedPerEndpointPools, clientWrapper.perEndpointPools)
+			require.Equal(t, tc.expectedRequestTimeout, clientWrapper.requestTimeout)
 			if tc.checkRequestDumpLogger {
 				require.NotNil(t, clientWrapper.requestDumpLogger)
 			}
@@ -155,6 +197,14 @@ func TestParseDocument(t *testing.T) {
 			expectedError: errors.New("performing request: random error"),
 		},
 	}
+	originalJsonMarshal := jsonMarshal
+	originalNewRequestWithContext := newRequestWithContext
+	originalNewHttpClient := newHttpClient
+	t.Cleanup(func() {
+		jsonMarshal = originalJsonMarshal
+		newRequestWithContext = originalNewRequestWithContext
+		newHttpClient = originalNewHttpClient
+	})
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			jsonMarshal = tc.mockJsonMarshal
@@ -177,6 +227,117 @@ func TestParseDocument(t *testing.T) {
 	}
 }
 
+func TestParseDocumentWithBase64Encoding(t *testing.T) {
+	fileContents := []byte{0xfb, 0xff}
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedOutput string
+	}{
+		{
+			name:           "standard encoding by default",
+			expectedOutput: "+/8=",
+		},
+		{
+			name:           "url encoding",
+			options:        []Option{WithBase64Encoding(base64.URLEncoding)},
+			expectedOutput: "-_8=",
+		},
+		{
+			name:           "raw url encoding",
+			options:        []Option{WithBase64Encoding(base64.RawURLEncoding)},
+			expectedOutput: "-_8",
+		},
+		{
+			name:           "nil encoding falls back to standard encoding",
+			options:        []Option{WithBase64Encoding(nil)},
+			expectedOutput: "+/8=",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			_, err := client.ParseDocument(context.TODO(), fileContents)
+			require.Nil(t, err)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+			var body parseDocumentRequest
+			require.Nil(t, json.NewDecoder(mock.Req.Body).Decode(&body))
+			require.Equal(t, tc.expectedOutput, body.Base64Data)
+		})
+	}
+}
+
+func TestParseDocumentHttpError(t *testing.T) {
+	testCases := []struct {
+		name               string
+		statusCode         int
+		body               string
+		expectedStatusCode int
+	}{
+		{
+			name:               "unparseable document",
+			statusCode:         http.StatusUnprocessableEntity,
+			body:               `{"error":"unsupported document"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:               "service unavailable",
+			statusCode:         http.StatusServiceUnavailable,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.ErrorContains(t, err, "performing request")
+			var httpErr *HttpError
+			require.True(t, errors.As(err, &httpErr))
+			require.Equal(t, tc.expectedStatusCode, httpErr.StatusCode)
+			require.Equal(t, tc.body, httpErr.Body)
+		})
+	}
+}
+
+func TestParseDocumentPreservesLanguagesOrder(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "decoding",
+		},
+		{
+			name: "normalization",
+			options: []Option{
+				WithDeterministicSkillOrder(),
+				WithNormalizeWhitespace(),
+				WithAcceptedDetectedLanguages("en"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana","languages":["French","English","Italian","Spanish"],"detected_language":"en"}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, []string{"French", "English", "Italian", "Spanish"}, resume.Languages)
+		})
+	}
+}
+
 func output() *Resume {
 	const layout = "2006-01-02 15:04:05 -0700 MST"
 
@@ -290,7 +451,7 @@ func output() *Resume {
 		},
 		SocialUrls:       []SocialUrl{},
 		PhoneNumbers:     []PhoneNumber{{CountryCode: "+1", CountryName: "US", NationalNumber: "(267) 721-0053"}},
-		Languages:        []string{"French", "Italian", "Spanish", "English"},
+		Languages:        []string{"French", "English", "Italian", "Spanish"},
 		DetectedLanguage: "en",
 		Skills: []Skill{
 			{Name: "Synaptic", NumMonths: 0},
@@ -447,7 +608,7 @@ func buildExpectedOutput() *Resume {
 		},
 		SocialUrls:       []SocialUrl{},
 		PhoneNumbers:     []PhoneNumber{{CountryCode: "+1", CountryName: "US", NationalNumber: "(267) 721-0053"}},
-		Languages:        []string{"French", "Italian", "Spanish", "English"},
+		Languages:        []string{"French", "English", "Italian", "Spanish"},
 		DetectedLanguage: "en",
 		Skills: []Skill{
 			{Name: "Synaptic", NumMonths: 0},
@@ -502,3 +663,46 @@ func (m *httpClientMock) SendRequestAndUnmarshallJsonResponse(req *http.Request,
 	*r = *output()
 	return m.Resp, m.Err
 }
+
+// resumeHttpClientMock responds to every request with the JSON of
+// Output, or of output() if nil, and keeps the last request sent.
+type resumeHttpClientMock struct {
+	httpclient.Client
+	Output *Resume
+	Req    *http.Request
+}
+
+func (m *resumeHttpClientMock) SendRequest(req *http.Request) (*http.Response, error) {
+	m.Req = req
+	resume := output()
+	if m.Output != nil {
+		resume = m.Output
+	}
+	body, err := json.Marshal(resume)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func (m *resumeHttpClientMock) SendRequestAndUnmarshallJsonResponse(req *http.Request, v any) (*http.Response, error) {
+	resp, err := m.SendRequest(req)
+	if err != nil {
+		return resp, err
+	}
+	return resp, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// newClientWithMock returns a client whose http client is
+// mocked to respond with the given resume.
+func newClientWithMock(t *testing.T, output *Resume, options ...Option) *resumeParsingServiceClient {
+	t.Helper()
+	client, ok := NewResumeParsingServiceClient("TOKEN", "URL", options...).(*resumeParsingServiceClient)
+	require.True(t, ok)
+	client.httpClient = &resumeHttpClientMock{Output: output}
+	return client
+}
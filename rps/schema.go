@@ -0,0 +1,71 @@
+package rps
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// responseSchema is the JSON Schema responses are
+// validated against, set through WithJSONSchemaValidation.
+type responseSchema struct {
+	schema *gojsonschema.Schema
+}
+
+// validResponseSchema compiles the JSON Schema set through
+// WithJSONSchemaValidation, if any. If it does not compile, it logs
+// a warning and returns nil, so that responses are not validated.
+func (r *resumeParsingServiceClient) validResponseSchema(schema []byte) *responseSchema {
+	if schema == nil {
+		return nil
+	}
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		logPrintf("%s: ignoring invalid response schema: %v", r.logPrefix(), err)
+		return nil
+	}
+	return &responseSchema{schema: compiled}
+}
+
+// validate returns an error listing the violations
+// of the schema by the raw response body.
+func (s *responseSchema) validate(raw []byte) error {
+	result, err := s.schema.Validate(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return errors.Wrap(err, "validating response")
+	}
+	if result.Valid() {
+		return nil
+	}
+	violations := make([]string, 0, len(result.Errors()))
+	for _, violation := range result.Errors() {
+		violations = append(violations, violation.String())
+	}
+	return errors.Errorf("response does not match schema: %s", strings.Join(violations, "; "))
+}
+
+// validatingTarget validates the raw response body
+// against the schema before decoding it into target.
+type validatingTarget struct {
+	schema *responseSchema
+	target any
+}
+
+// validating returns the value the response is decoded into, which
+// is target itself unless WithJSONSchemaValidation is set.
+func (r *resumeParsingServiceClient) validating(target any) any {
+	if r.responseSchema == nil {
+		return target
+	}
+	return &validatingTarget{schema: r.responseSchema, target: target}
+}
+
+// UnmarshalJSON validates the response body and decodes it into the target.
+func (v *validatingTarget) UnmarshalJSON(b []byte) error {
+	if err := v.schema.validate(b); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v.target)
+}
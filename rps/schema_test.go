@@ -0,0 +1,95 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testResponseSchema = `{
+	"type": "object",
+	"required": ["first_name", "last_name"],
+	"properties": {
+		"first_name": {"type": "string"},
+		"last_name": {"type": "string"}
+	}
+}`
+
+func TestParseDocumentWithJSONSchemaValidation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		responseBody     string
+		expectedOutput   *Resume
+		expectedError    error
+		expectedWarnings []string
+	}{
+		{
+			name:           "no validation by default",
+			responseBody:   `{"first_name":"Morgana"}`,
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:           "conforming response",
+			options:        []Option{WithJSONSchemaValidation([]byte(testResponseSchema))},
+			responseBody:   `{"first_name":"Morgana","last_name":"Le Fay"}`,
+			expectedOutput: &Resume{FirstName: "Morgana", LastName: "Le Fay"},
+		},
+		{
+			name:          "non-conforming response",
+			options:       []Option{WithJSONSchemaValidation([]byte(testResponseSchema))},
+			responseBody:  `{"first_name":42}`,
+			expectedError: errors.New("performing request: decoding response: response does not match schema: (root): last_name is required; first_name: Invalid type. Expected: string, given: integer"),
+		},
+		{
+			name:             "invalid schema is ignored",
+			options:          []Option{WithJSONSchemaValidation([]byte(`{"type":`))},
+			responseBody:     `{"first_name":"Morgana"}`,
+			expectedOutput:   &Resume{FirstName: "Morgana"},
+			expectedWarnings: []string{"rps: ignoring invalid response schema: unexpected EOF"},
+		},
+		{
+			name: "enveloped response",
+			options: []Option{
+				WithResponseEnvelope("data"),
+				WithJSONSchemaValidation([]byte(`{"type":"object","required":["data"]}`)),
+			},
+			responseBody:   `{"data":{"first_name":"Morgana"}}`,
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+	}
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.responseBody))
+			}))
+			defer svr.Close()
+			var warnings []string
+			logPrintf = func(format string, v ...any) {
+				warnings = append(warnings, fmt.Sprintf(format, v...))
+			}
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, tc.options...)
+			require.Equal(t, tc.expectedWarnings, warnings)
+			output, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
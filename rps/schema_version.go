@@ -0,0 +1,55 @@
+package rps
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// schemaVersionKey is the key of the version of the output
+// schema of the service, when it returns one.
+const schemaVersionKey = "schema_version"
+
+// schemaVersionValue returns the schema version held by a JSON value,
+// either a string or a number. Other values are ignored.
+func schemaVersionValue(value json.RawMessage) string {
+	var version any
+	if err := json.Unmarshal(value, &version); err != nil {
+		return ""
+	}
+	switch version := version.(type) {
+	case string:
+		return strings.TrimSpace(version)
+	case float64:
+		return string(bytes.TrimSpace(value))
+	default:
+		return ""
+	}
+}
+
+// schemaVersion returns the version of the output schema found at the
+// top level of the raw response or, with WithResponseEnvelope, of its
+// data or metadata. It is empty if absent, or if the response is not
+// JSON.
+func (r *resumeParsingServiceClient) schemaVersion(raw []byte) string {
+	if !bytes.Contains(raw, []byte(`"`+schemaVersionKey+`"`)) {
+		return ""
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ""
+	}
+	if version := schemaVersionValue(fields[schemaVersionKey]); version != "" || r.envelopeDataKey == "" {
+		return version
+	}
+	for _, key := range []string{r.envelopeDataKey, envelopeMetaKey} {
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(fields[key], &nested); err != nil {
+			continue
+		}
+		if version := schemaVersionValue(nested[schemaVersionKey]); version != "" {
+			return version
+		}
+	}
+	return ""
+}
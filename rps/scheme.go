@@ -0,0 +1,19 @@
+package rps
+
+import "strings"
+
+const (
+	httpScheme  = "http://"
+	httpsScheme = "https://"
+)
+
+// upgradeToHTTPS rewrites an http:// base URL to https://, logging a
+// warning, as the service requires HTTPS. Other URLs are untouched.
+func (r *resumeParsingServiceClient) upgradeToHTTPS(baseUrl string) string {
+	if len(baseUrl) < len(httpScheme) || !strings.EqualFold(baseUrl[:len(httpScheme)], httpScheme) {
+		return baseUrl
+	}
+	upgraded := httpsScheme + baseUrl[len(httpScheme):]
+	logPrintf("%s: upgrading base URL %q to %q, as the service requires HTTPS", r.logPrefix(), baseUrl, upgraded)
+	return upgraded
+}
@@ -0,0 +1,112 @@
+package rps
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResumeParsingServiceClientWithHTTPSUpgrade(t *testing.T) {
+	testCases := []struct {
+		name             string
+		baseUrl          string
+		options          []Option
+		expectedBaseUrl  string
+		expectedWarnings []string
+	}{
+		{
+			name:             "http is upgraded",
+			baseUrl:          "http://rps.example.com",
+			options:          []Option{WithHTTPSUpgrade()},
+			expectedBaseUrl:  "https://rps.example.com",
+			expectedWarnings: []string{`rps: upgrading base URL "http://rps.example.com" to "https://rps.example.com", as the service requires HTTPS`},
+		},
+		{
+			name:             "scheme is case-insensitive",
+			baseUrl:          "HTTP://rps.example.com",
+			options:          []Option{WithHTTPSUpgrade()},
+			expectedBaseUrl:  "https://rps.example.com",
+			expectedWarnings: []string{`rps: upgrading base URL "HTTP://rps.example.com" to "https://rps.example.com", as the service requires HTTPS`},
+		},
+		{
+			name:             "warning is tagged with the client name",
+			baseUrl:          "http://rps.example.com",
+			options:          []Option{WithHTTPSUpgrade(), WithClientName("premium")},
+			expectedBaseUrl:  "https://rps.example.com",
+			expectedWarnings: []string{`rps[premium]: upgrading base URL "http://rps.example.com" to "https://rps.example.com", as the service requires HTTPS`},
+		},
+		{
+			name:            "https is untouched",
+			baseUrl:         "https://rps.example.com",
+			options:         []Option{WithHTTPSUpgrade()},
+			expectedBaseUrl: "https://rps.example.com",
+		},
+		{
+			name:            "http is untouched by default",
+			baseUrl:         "http://rps.example.com",
+			expectedBaseUrl: "http://rps.example.com",
+		},
+	}
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []string
+			logPrintf = func(format string, v ...any) {
+				warnings = append(warnings, fmt.Sprintf(format, v...))
+			}
+			client, ok := NewResumeParsingServiceClient("TOKEN", tc.baseUrl, tc.options...).(*resumeParsingServiceClient)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedBaseUrl, client.rioParseBaseUrl)
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestParseDocumentWithHTTPSUpgradeAndBaseUrlOverride(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []Option
+		baseUrl          string
+		expectedUrl      string
+		expectedWarnings []string
+	}{
+		{
+			name:             "http override is upgraded",
+			options:          []Option{WithHTTPSUpgrade()},
+			baseUrl:          "http://canary.example.com",
+			expectedUrl:      "https://canary.example.com/api/parse",
+			expectedWarnings: []string{`rps: upgrading base URL "http://canary.example.com" to "https://canary.example.com", as the service requires HTTPS`},
+		},
+		{
+			name:        "https override is untouched",
+			options:     []Option{WithHTTPSUpgrade()},
+			baseUrl:     "https://canary.example.com",
+			expectedUrl: "https://canary.example.com/api/parse",
+		},
+		{
+			name:        "http override is untouched by default",
+			baseUrl:     "http://canary.example.com",
+			expectedUrl: "http://canary.example.com/api/parse",
+		},
+	}
+	originalLogPrintf := logPrintf
+	defer func() { logPrintf = originalLogPrintf }()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+			var warnings []string
+			logPrintf = func(format string, v ...any) {
+				warnings = append(warnings, fmt.Sprintf(format, v...))
+			}
+			_, err := client.ParseDocument(context.TODO(), []byte{}, WithBaseUrlOverride(tc.baseUrl))
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedUrl, mock.Req.URL.String())
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
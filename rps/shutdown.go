@@ -0,0 +1,56 @@
+package rps
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrClientClosed is returned by the calls made after Shutdown.
+var ErrClientClosed = errors.New("client closed")
+
+// lifecycle tracks the in-flight calls of a client,
+// rejecting new ones once it is shut down.
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+// begin registers a new in-flight call, returning
+// ErrClientClosed if the client is shut down.
+func (l *lifecycle) begin() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClientClosed
+	}
+	l.inFlight.Add(1)
+	return nil
+}
+
+// end unregisters an in-flight call.
+func (l *lifecycle) end() {
+	l.inFlight.Done()
+}
+
+// Shutdown stops accepting new calls, which fail with ErrClientClosed,
+// and waits for the in-flight ones to complete or for the context to be
+// done, in which case the context error is returned.
+func (r *resumeParsingServiceClient) Shutdown(ctx context.Context) error {
+	r.lifecycle.mu.Lock()
+	r.lifecycle.closed = true
+	r.lifecycle.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		r.lifecycle.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
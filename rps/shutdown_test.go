@@ -0,0 +1,82 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockingServer starts a server signaling each received request
+// through received, and responding once release is closed.
+func newBlockingServer(received chan<- struct{}, release <-chan struct{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"first_name":"Morgana"}`))
+	}))
+}
+
+// isClosed reports whether the client was shut down.
+func isClosed(client *resumeParsingServiceClient) bool {
+	client.lifecycle.mu.Lock()
+	defer client.lifecycle.mu.Unlock()
+	return client.lifecycle.closed
+}
+
+func TestShutdown(t *testing.T) {
+	received, release := make(chan struct{}, 1), make(chan struct{})
+	svr := newBlockingServer(received, release)
+	defer svr.Close()
+	client, ok := NewResumeParsingServiceClient("TOKEN", svr.URL).(*resumeParsingServiceClient)
+	require.True(t, ok)
+
+	inFlight := make(chan error, 1)
+	go func() {
+		_, err := client.ParseDocument(context.TODO(), []byte{})
+		inFlight <- err
+	}()
+	<-received
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- client.Shutdown(context.TODO())
+	}()
+	require.Eventually(t, func() bool {
+		return isClosed(client)
+	}, time.Second, time.Millisecond)
+
+	_, err := client.ParseDocument(context.TODO(), []byte{})
+	require.ErrorIs(t, err, ErrClientClosed)
+	_, err = client.ParseDocumentBundle(context.TODO(), [][]byte{{}})
+	require.ErrorIs(t, err, ErrClientClosed)
+	select {
+	case <-shutdown:
+		t.Fatal("expected shutdown to wait for the in-flight call")
+	default:
+	}
+
+	close(release)
+	require.Nil(t, <-inFlight)
+	require.Nil(t, <-shutdown)
+}
+
+func TestShutdownWithExpiredContext(t *testing.T) {
+	received, release := make(chan struct{}, 1), make(chan struct{})
+	svr := newBlockingServer(received, release)
+	defer svr.Close()
+	defer close(release)
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+
+	go func() {
+		_, _ = client.ParseDocument(context.TODO(), []byte{})
+	}()
+	<-received
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	err := client.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
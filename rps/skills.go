@@ -0,0 +1,55 @@
+package rps
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SkillsByCategory groups the skills by category, preserving their
+// order. Skills without a category are grouped under the empty string.
+// It returns nil if the resume has no skills.
+func (r *Resume) SkillsByCategory() map[string][]Skill {
+	if len(r.Skills) == 0 {
+		return nil
+	}
+	categories := make(map[string][]Skill)
+	for _, skill := range r.Skills {
+		categories[skill.Category] = append(categories[skill.Category], skill)
+	}
+	return categories
+}
+
+// MatchesName reports whether the query is the name or one
+// of the aliases of the skill, ignoring case and surrounding
+// whitespace, to improve the recall of skill searches. An empty
+// query matches no skill, not even the ones without a name.
+func (s Skill) MatchesName(query string) bool {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSpace(s.Name), query) {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if strings.EqualFold(strings.TrimSpace(alias), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropLongSkills drops the skills whose name, once trimmed, is
+// longer than n characters, preserving the order of the others.
+func dropLongSkills(resume *Resume, n int) {
+	if resume.Skills == nil {
+		return
+	}
+	kept := resume.Skills[:0]
+	for _, skill := range resume.Skills {
+		if utf8.RuneCountInString(strings.TrimSpace(skill.Name)) <= n {
+			kept = append(kept, skill)
+		}
+	}
+	resume.Skills = kept
+}
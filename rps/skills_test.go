@@ -0,0 +1,186 @@
+package rps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillsByCategory(t *testing.T) {
+	testCases := []struct {
+		name               string
+		input              string
+		expectedSkills     []Skill
+		expectedCategories map[string][]Skill
+	}{
+		{
+			name:  "with categories",
+			input: `{"skills":[{"name":"Teamwork","num_months":0,"category":"soft"},{"name":"Electrophysiology","num_months":31,"category":"technical"},{"name":"Editing","num_months":0,"category":"soft"}]}`,
+			expectedSkills: []Skill{
+				{Name: "Teamwork", NumMonths: 0, Category: "soft"},
+				{Name: "Electrophysiology", NumMonths: 31, Category: "technical"},
+				{Name: "Editing", NumMonths: 0, Category: "soft"},
+			},
+			expectedCategories: map[string][]Skill{
+				"soft": {
+					{Name: "Teamwork", NumMonths: 0, Category: "soft"},
+					{Name: "Editing", NumMonths: 0, Category: "soft"},
+				},
+				"technical": {
+					{Name: "Electrophysiology", NumMonths: 31, Category: "technical"},
+				},
+			},
+		},
+		{
+			name:  "without categories",
+			input: `{"skills":[{"name":"Teamwork","num_months":0},{"name":"Electrophysiology","num_months":31}]}`,
+			expectedSkills: []Skill{
+				{Name: "Teamwork", NumMonths: 0},
+				{Name: "Electrophysiology", NumMonths: 31},
+			},
+			expectedCategories: map[string][]Skill{
+				"": {
+					{Name: "Teamwork", NumMonths: 0},
+					{Name: "Electrophysiology", NumMonths: 31},
+				},
+			},
+		},
+		{
+			name:  "without skills",
+			input: `{}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resume Resume
+			require.Nil(t, json.Unmarshal([]byte(tc.input), &resume))
+			require.Equal(t, tc.expectedSkills, resume.Skills)
+			require.Equal(t, tc.expectedCategories, resume.SkillsByCategory())
+		})
+	}
+}
+
+func TestSkillAliases(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedSkills []Skill
+	}{
+		{
+			name:  "with aliases",
+			input: `{"skills":[{"name":"Go","num_months":24,"aliases":["Golang"]},{"name":"JavaScript","num_months":12,"aliases":["JS","ECMAScript"]}]}`,
+			expectedSkills: []Skill{
+				{Name: "Go", NumMonths: 24, Aliases: []string{"Golang"}},
+				{Name: "JavaScript", NumMonths: 12, Aliases: []string{"JS", "ECMAScript"}},
+			},
+		},
+		{
+			name:           "without aliases",
+			input:          `{"skills":[{"name":"Go","num_months":24}]}`,
+			expectedSkills: []Skill{{Name: "Go", NumMonths: 24}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resume Resume
+			require.Nil(t, json.Unmarshal([]byte(tc.input), &resume))
+			require.Equal(t, tc.expectedSkills, resume.Skills)
+		})
+	}
+}
+
+func TestSkillMatchesName(t *testing.T) {
+	skill := Skill{Name: "Go", Aliases: []string{"Golang", "Go lang"}}
+	testCases := []struct {
+		name           string
+		query          string
+		expectedOutput bool
+	}{
+		{
+			name:           "name",
+			query:          "Go",
+			expectedOutput: true,
+		},
+		{
+			name:           "name is case-insensitive",
+			query:          "GO",
+			expectedOutput: true,
+		},
+		{
+			name:           "alias",
+			query:          "golang",
+			expectedOutput: true,
+		},
+		{
+			name:           "alias with surrounding whitespace",
+			query:          " go lang ",
+			expectedOutput: true,
+		},
+		{
+			name:  "partial name",
+			query: "Gol",
+		},
+		{
+			name:  "empty query",
+			query: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, skill.MatchesName(tc.query))
+		})
+	}
+	t.Run("empty query and skill without name", func(t *testing.T) {
+		require.False(t, Skill{Aliases: []string{""}}.MatchesName(" "))
+	})
+}
+
+func TestParseDocumentWithMaxSkillNameLength(t *testing.T) {
+	sentence := "Responsible for leading a team of engineers building distributed systems"
+	testCases := []struct {
+		name           string
+		options        []Option
+		skills         []Skill
+		expectedSkills []Skill
+	}{
+		{
+			name:           "skills are kept by default",
+			skills:         []Skill{{Name: "Go"}, {Name: sentence}},
+			expectedSkills: []Skill{{Name: "Go"}, {Name: sentence}},
+		},
+		{
+			name:           "oversized names are dropped",
+			options:        []Option{WithMaxSkillNameLength(20)},
+			skills:         []Skill{{Name: "Go"}, {Name: sentence}, {Name: "Project Management"}},
+			expectedSkills: []Skill{{Name: "Go"}, {Name: "Project Management"}},
+		},
+		{
+			name:           "length is counted in characters, once trimmed",
+			options:        []Option{WithMaxSkillNameLength(6)},
+			skills:         []Skill{{Name: " Façade "}, {Name: "Façades!"}},
+			expectedSkills: []Skill{{Name: " Façade "}},
+		},
+		{
+			name:           "all skills dropped",
+			options:        []Option{WithMaxSkillNameLength(1)},
+			skills:         []Skill{{Name: "Go"}},
+			expectedSkills: []Skill{},
+		},
+		{
+			name:           "non-positive length is ignored",
+			options:        []Option{WithMaxSkillNameLength(0)},
+			skills:         []Skill{{Name: sentence}},
+			expectedSkills: []Skill{{Name: sentence}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{Skills: tc.skills}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedSkills, resume.Skills)
+		})
+	}
+}
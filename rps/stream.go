@@ -0,0 +1,136 @@
+package rps
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// Events of a streamed parse. Events without a name are partial updates.
+const (
+	partialEvent  = "partial"
+	completeEvent = "complete"
+	errorEvent    = "error"
+)
+
+// PartialResume is a partial update of a streamed parse,
+// holding the fields extracted so far.
+type PartialResume struct {
+	Resume
+}
+
+// serverSentEvent is an event of a text/event-stream response.
+type serverSentEvent struct {
+	name string
+	data string
+}
+
+// readServerSentEvent reads the next event of the stream, skipping
+// comments and fields other than event and data. As per the
+// specification, events without data are ignored, and so is an
+// unterminated event at the end of the stream, where io.EOF is returned.
+func readServerSentEvent(reader *bufio.Reader) (*serverSentEvent, error) {
+	var event serverSentEvent
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if data != nil {
+				event.data = strings.Join(data, "\n")
+				return &event, nil
+			}
+			event = serverSentEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+// readResumeStream reads the events of a streamed parse, passing the
+// partial updates to onEvent, until the complete resume is received.
+// The complete resume is decoded into target, and the raw data of the
+// complete event is returned.
+func readResumeStream(body io.Reader, target any, onEvent func(PartialResume)) ([]byte, error) {
+	reader := bufio.NewReader(body)
+	for {
+		event, err := readServerSentEvent(reader)
+		if err == io.EOF {
+			return nil, errors.New("stream ended before the complete resume")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading event stream")
+		}
+		switch event.name {
+		case "", partialEvent:
+			var partial PartialResume
+			if err := json.Unmarshal([]byte(event.data), &partial); err != nil {
+				return nil, errors.Wrap(err, "decoding partial event")
+			}
+			if onEvent != nil {
+				onEvent(partial)
+			}
+		case completeEvent:
+			if err := json.Unmarshal([]byte(event.data), target); err != nil {
+				return nil, errors.Wrap(err, "decoding complete event")
+			}
+			return []byte(event.data), nil
+		case errorEvent:
+			return nil, errors.Errorf("parsing document: %s", event.data)
+		}
+	}
+}
+
+// eventStreamDecoder decodes the complete resume of a text/event-stream
+// response, passing the partial updates to onEvent, and keeps the raw
+// data of the complete event.
+type eventStreamDecoder struct {
+	onEvent  func(PartialResume)
+	complete []byte
+}
+
+// Decode decodes the complete resume of the stream read from r into v.
+func (d *eventStreamDecoder) Decode(r io.Reader, v any) error {
+	complete, err := readResumeStream(r, v, d.onEvent)
+	d.complete = complete
+	return err
+}
+
+// ParseDocumentStreaming sends a resume document for parsing, requesting
+// the service to stream partial updates as text/event-stream, and
+// passes them to onEvent as fields are extracted. It returns the
+// complete resume once received. When the service responds with JSON
+// instead, the resume is returned without partial updates.
+func (r *resumeParsingServiceClient) ParseDocumentStreaming(ctx context.Context, fileContents []byte, onEvent func(PartialResume), options ...CallOption) (*Resume, error) {
+	if err := r.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer r.lifecycle.end()
+	call := r.newCallOptions(options)
+	call.eventStream = &eventStreamDecoder{onEvent: r.guardEventCallback(onEvent)}
+	start := time.Now()
+	var resume Resume
+	err := r.parseDocument(ctx, fileContents, &resume, call)
+	r.observeLatency(time.Since(start), call.metadata.StatusCode)
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
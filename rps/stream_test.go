@@ -0,0 +1,174 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentStreaming(t *testing.T) {
+	testCases := []struct {
+		name             string
+		contentType      string
+		events           []string
+		expectedPartials []PartialResume
+		expectedOutput   *Resume
+		expectedError    error
+	}{
+		{
+			name:        "partial events then complete resume",
+			contentType: "text/event-stream",
+			events: []string{
+				": parsing started\n\n",
+				"event: partial\ndata: {\"first_name\":\"Morgana\"}\n\n",
+				"data: {\"first_name\":\"Morgana\",\n",
+				"data: \"last_name\":\"Pendragon\"}\n\n",
+				"id: 3\nevent: complete\ndata: {\"first_name\":\"Morgana\",\"last_name\":\"Pendragon\",\"emails\":[\"morgana@camelot.com\"]}\n\n",
+			},
+			expectedPartials: []PartialResume{
+				{Resume: Resume{FirstName: "Morgana"}},
+				{Resume: Resume{FirstName: "Morgana", LastName: "Pendragon"}},
+			},
+			expectedOutput: &Resume{FirstName: "Morgana", LastName: "Pendragon", Emails: []string{"morgana@camelot.com"}},
+		},
+		{
+			name:        "crlf line endings",
+			contentType: "text/event-stream; charset=utf-8",
+			events: []string{
+				"event: complete\r\ndata: {\"first_name\":\"Morgana\"}\r\n\r\n",
+			},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:        "error event",
+			contentType: "text/event-stream",
+			events: []string{
+				"event: partial\ndata: {\"first_name\":\"Morgana\"}\n\n",
+				"event: error\ndata: unsupported document\n\n",
+			},
+			expectedPartials: []PartialResume{{Resume: Resume{FirstName: "Morgana"}}},
+			expectedError:    errors.New("performing request: decoding response: parsing document: unsupported document"),
+		},
+		{
+			name:        "stream ended before the complete resume",
+			contentType: "text/event-stream",
+			events: []string{
+				"event: partial\ndata: {\"first_name\":\"Morgana\"}\n\n",
+				"event: complete\ndata: {\"first_name\":\"Morgana\"}",
+			},
+			expectedPartials: []PartialResume{{Resume: Resume{FirstName: "Morgana"}}},
+			expectedError:    errors.New("performing request: decoding response: stream ended before the complete resume"),
+		},
+		{
+			name:          "invalid partial event",
+			contentType:   "text/event-stream",
+			events:        []string{"data: {\n\n"},
+			expectedError: errors.New("performing request: decoding response: decoding partial event: unexpected end of JSON input"),
+		},
+		{
+			name:           "json response without streaming",
+			contentType:    "application/json",
+			events:         []string{`{"first_name":"Morgana"}`},
+			expectedOutput: &Resume{FirstName: "Morgana"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Accept") != "text/event-stream" {
+					w.WriteHeader(http.StatusNotAcceptable)
+					return
+				}
+				w.Header().Set("Content-Type", tc.contentType)
+				flusher, _ := w.(http.Flusher)
+				for _, event := range tc.events {
+					_, _ = w.Write([]byte(event))
+					flusher.Flush()
+				}
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			var partials []PartialResume
+			output, err := client.ParseDocumentStreaming(context.TODO(), []byte{}, func(partial PartialResume) {
+				partials = append(partials, partial)
+			})
+			require.Equal(t, tc.expectedPartials, partials)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			require.Nil(t, tc.expectedError)
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestParseDocumentStreamingPostProcessing(t *testing.T) {
+	const complete = `{"first_name":"Morgana","emails":["Favero.Morgana@gmail.com"]}`
+	var requests int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("data: {\"first_name\":\"Morgana\"}\n\nevent: complete\ndata: " + complete + "\n\n"))
+	}))
+	defer svr.Close()
+	var hookRaws []string
+	cache := NewMemoryETagCache()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL,
+		WithEmailNormalization(),
+		WithETagCache(cache),
+		WithResponseHook(func(resume *Resume, raw []byte) {
+			hookRaws = append(hookRaws, string(raw))
+		}),
+	)
+	for i := 0; i < 2; i++ {
+		var partials int
+		output, err := client.ParseDocumentStreaming(context.TODO(), []byte("file"), func(PartialResume) {
+			partials++
+		})
+		require.Nil(t, err)
+		require.Equal(t, []string{"favero.morgana@gmail.com"}, output.Emails)
+		// The second response is answered from the cache, without events.
+		require.Equal(t, 1-i, partials)
+	}
+	require.Equal(t, 2, requests)
+	require.Equal(t, []string{complete, complete}, hookRaws)
+	entry, ok := cache.Get(etagCacheKey([]byte("file"), etagCacheVariant{
+		url:          svr.URL + "/api/parse",
+		accept:       "text/event-stream",
+		modelVersion: ModelV1,
+	}))
+	require.True(t, ok)
+	require.Equal(t, "application/json", entry.ContentType)
+}
+
+func TestParseDocumentStreamingWithDecodeTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"first_name\":\"Morgana\"}\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithDecodeTimeout(50*time.Millisecond))
+	start := time.Now()
+	_, err := client.ParseDocumentStreaming(context.TODO(), []byte{}, nil)
+	require.ErrorIs(t, err, ErrDecodeTimeout)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,66 @@
+package rps
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// byteOrderMark is the UTF-8 encoded byte order mark.
+const byteOrderMark = "\ufeff"
+
+// mapStrings replaces every string field reachable from v, through
+// structs, pointers and slices, with the result of f applied to it.
+// v must be a pointer.
+func mapStrings(v any, f func(string) string) {
+	mapValueStrings(reflect.ValueOf(v), f)
+}
+
+// mapValueStrings is the recursive step of mapStrings.
+func mapValueStrings(v reflect.Value, f func(string) string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			mapValueStrings(v.Elem(), f)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				mapValueStrings(v.Field(i), f)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			mapValueStrings(v.Index(i), f)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(f(v.String()))
+		}
+	}
+}
+
+// stripByteOrderMark removes a leading byte order mark from
+// every string field of the resume.
+func stripByteOrderMark(resume *Resume) {
+	mapStrings(resume, func(s string) string {
+		return strings.TrimPrefix(s, byteOrderMark)
+	})
+}
+
+// stripControlChars removes the control characters, such as null
+// bytes and form feeds, from s, except tabs and line breaks.
+func stripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// stripResumeControlChars removes the control characters
+// from every string field of the resume.
+func stripResumeControlChars(resume *Resume) {
+	mapStrings(resume, stripControlChars)
+}
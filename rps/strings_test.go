@@ -0,0 +1,114 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentStripsByteOrderMark(t *testing.T) {
+	client := newClientWithMock(t, &Resume{
+		FirstName: "\ufeffMorgana",
+		Emails:    []string{"\ufefffavero.morgana@gmail.com"},
+		Positions: []Position{
+			{Title: "\ufeffPostdoctoral Researcher", Location: Location{City: "\ufeffPhiladelphia"}},
+		},
+		RawText: "\ufeffMORGANA FAVERO, MD, PhD \ufeff",
+	})
+	resume, err := client.ParseDocument(context.TODO(), []byte{})
+	require.Nil(t, err)
+	require.Equal(t, &Resume{
+		FirstName: "Morgana",
+		Emails:    []string{"favero.morgana@gmail.com"},
+		Positions: []Position{
+			{Title: "Postdoctoral Researcher", Location: Location{City: "Philadelphia"}},
+		},
+		RawText: "MORGANA FAVERO, MD, PhD \ufeff",
+	}, resume)
+}
+
+func TestStripControlChars(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "null bytes",
+			input:          "Mor\x00gana\x00",
+			expectedOutput: "Morgana",
+		},
+		{
+			name:           "form feeds and other control characters",
+			input:          "Page 1\fPage 2\x1b\x7f\u0085",
+			expectedOutput: "Page 1Page 2",
+		},
+		{
+			name:           "tabs and line breaks are kept",
+			input:          "Skills:\tGo\r\nPython\n",
+			expectedOutput: "Skills:\tGo\r\nPython\n",
+		},
+		{
+			name:           "printable characters are kept",
+			input:          "Zoë Müller – Engineer",
+			expectedOutput: "Zoë Müller – Engineer",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, stripControlChars(tc.input))
+		})
+	}
+}
+
+func TestParseDocumentWithControlCharStripping(t *testing.T) {
+	input := &Resume{
+		FirstName: "Morgana\x00",
+		Emails:    []string{"favero.morgana@gmail.com\x00"},
+		Positions: []Position{
+			{Title: "Postdoctoral\x0b Researcher", Description: "Line 1\nLine 2\f"},
+		},
+		Skills:  []Skill{{Name: "Electro\x00physiology", Aliases: []string{"\x01EP"}}},
+		RawText: "MORGANA FAVERO\f\nMD, PhD",
+	}
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedOutput *Resume
+	}{
+		{
+			name: "stripping is disabled by default",
+			expectedOutput: &Resume{
+				FirstName: "Morgana\x00",
+				Emails:    []string{"favero.morgana@gmail.com\x00"},
+				Positions: []Position{
+					{Title: "Postdoctoral\x0b Researcher", Description: "Line 1\nLine 2\f"},
+				},
+				Skills:  []Skill{{Name: "Electro\x00physiology", Aliases: []string{"\x01EP"}}},
+				RawText: "MORGANA FAVERO\f\nMD, PhD",
+			},
+		},
+		{
+			name:    "enabled",
+			options: []Option{WithControlCharStripping()},
+			expectedOutput: &Resume{
+				FirstName: "Morgana",
+				Emails:    []string{"favero.morgana@gmail.com"},
+				Positions: []Position{
+					{Title: "Postdoctoral Researcher", Description: "Line 1\nLine 2"},
+				},
+				Skills:  []Skill{{Name: "Electrophysiology", Aliases: []string{"EP"}}},
+				RawText: "MORGANA FAVERO\nMD, PhD",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, input, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type extendedResume struct {
+	Resume
+	Hobbies []string `json:"hobbies"`
+}
+
+func TestParseDocumentInto(t *testing.T) {
+	var nilResume *extendedResume
+	testCases := []struct {
+		name           string
+		target         any
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			// Normalization only applies to *Resume targets.
+			name:   "custom struct embedding resume",
+			target: new(extendedResume),
+			expectedOutput: &extendedResume{
+				Resume:  Resume{FirstName: "Morgana", Emails: []string{"Favero.Morgana@gmail.com"}},
+				Hobbies: []string{"climbing"},
+			},
+		},
+		{
+			name:           "resume",
+			target:         new(Resume),
+			expectedOutput: &Resume{FirstName: "Morgana", Emails: []string{"favero.morgana@gmail.com"}},
+		},
+		{
+			name:          "nil target",
+			expectedError: errors.New("target must be a non-nil pointer, got <nil>"),
+		},
+		{
+			name:          "non-pointer target",
+			target:        extendedResume{},
+			expectedError: errors.New("target must be a non-nil pointer, got rps.extendedResume"),
+		},
+		{
+			name:          "nil pointer target",
+			target:        nilResume,
+			expectedError: errors.New("target must be a non-nil pointer, got *rps.extendedResume"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"first_name":"Morgana","emails":["Favero.Morgana@gmail.com"],"hobbies":["climbing"]}`))
+			}))
+			defer svr.Close()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithEmailNormalization())
+			err := client.ParseDocumentInto(context.TODO(), []byte{}, tc.target)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, tc.expectedOutput, tc.target)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package rps
+
+import (
+	"sort"
+	"time"
+)
+
+// TimelineEntryKind is the kind of entry of a career timeline.
+type TimelineEntryKind string
+
+// Timeline entry kinds.
+const (
+	TimelineEntryPosition  TimelineEntryKind = "Position"
+	TimelineEntryEducation TimelineEntryKind = "Education"
+)
+
+// TimelineEntry is a position or an education in a career timeline.
+type TimelineEntry struct {
+	Kind      TimelineEntryKind
+	StartDate *time.Time
+	EndDate   *time.Time
+	Label     string
+}
+
+// label joins a title and an organization, either of which may be empty.
+func label(title, organization string) string {
+	switch {
+	case title == "":
+		return organization
+	case organization == "":
+		return title
+	default:
+		return title + " at " + organization
+	}
+}
+
+// sortDate returns the date used to place the entry in the timeline:
+// its start date or, if missing, its end date.
+func (e TimelineEntry) sortDate() *time.Time {
+	if e.StartDate != nil {
+		return e.StartDate
+	}
+	return e.EndDate
+}
+
+// before reports whether the entry comes before another one in
+// the timeline. Undated entries come last.
+func (e TimelineEntry) before(another TimelineEntry) bool {
+	date, anotherDate := e.sortDate(), another.sortDate()
+	switch {
+	case date == nil:
+		return false
+	case anotherDate == nil:
+		return true
+	default:
+		return date.Before(*anotherDate)
+	}
+}
+
+// Timeline merges positions and educations into a single list sorted
+// chronologically by start date (or end date, if the start date is
+// missing). Undated entries are placed last, and entries with the
+// same date keep their original order, positions first.
+func (r *Resume) Timeline() []TimelineEntry {
+	timeline := make([]TimelineEntry, 0, len(r.Positions)+len(r.Educations))
+	for _, position := range r.Positions {
+		timeline = append(timeline, TimelineEntry{
+			Kind:      TimelineEntryPosition,
+			StartDate: position.StartDate,
+			EndDate:   position.EndDate,
+			Label:     label(position.Title, position.Organization),
+		})
+	}
+	for _, education := range r.Educations {
+		timeline = append(timeline, TimelineEntry{
+			Kind:      TimelineEntryEducation,
+			StartDate: education.StartDate,
+			EndDate:   education.EndDate,
+			Label:     label(education.Degree, education.Organization),
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].before(timeline[j])
+	})
+	return timeline
+}
@@ -0,0 +1,73 @@
+package rps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// date returns a pointer to the first day of the given month.
+func date(year int, month time.Month) *time.Time {
+	d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func TestTimeline(t *testing.T) {
+	testCases := []struct {
+		name           string
+		resume         *Resume
+		expectedOutput []TimelineEntry
+	}{
+		{
+			name:           "empty resume",
+			resume:         &Resume{},
+			expectedOutput: []TimelineEntry{},
+		},
+		{
+			name: "mixed entries",
+			resume: &Resume{
+				Positions: []Position{
+					{Title: "Postdoctoral Researcher", Organization: "CHOP", StartDate: date(2015, time.November)},
+					{Title: "Assistant Professor", Organization: "University of Verona", StartDate: date(2013, time.March), EndDate: date(2015, time.October)},
+					{Organization: "Drexel University", StartDate: date(2009, time.January), EndDate: date(2013, time.February)},
+				},
+				Educations: []Education{
+					{Degree: "Doctor of Philosophy", Organization: "University of Verona", StartDate: date(2002, time.January), EndDate: date(2008, time.January)},
+					{Degree: "MD", StartDate: date(1995, time.January), EndDate: date(2002, time.January)},
+				},
+			},
+			expectedOutput: []TimelineEntry{
+				{Kind: TimelineEntryEducation, StartDate: date(1995, time.January), EndDate: date(2002, time.January), Label: "MD"},
+				{Kind: TimelineEntryEducation, StartDate: date(2002, time.January), EndDate: date(2008, time.January), Label: "Doctor of Philosophy at University of Verona"},
+				{Kind: TimelineEntryPosition, StartDate: date(2009, time.January), EndDate: date(2013, time.February), Label: "Drexel University"},
+				{Kind: TimelineEntryPosition, StartDate: date(2013, time.March), EndDate: date(2015, time.October), Label: "Assistant Professor at University of Verona"},
+				{Kind: TimelineEntryPosition, StartDate: date(2015, time.November), Label: "Postdoctoral Researcher at CHOP"},
+			},
+		},
+		{
+			name: "nil dates and overlaps",
+			resume: &Resume{
+				Positions: []Position{
+					{Title: "Undated"},
+					{Title: "Consultant", StartDate: date(2010, time.June), EndDate: date(2012, time.June)},
+					{Title: "Teacher", EndDate: date(2011, time.January)},
+				},
+				Educations: []Education{
+					{Degree: "Master", StartDate: date(2010, time.June), EndDate: date(2011, time.June)},
+				},
+			},
+			expectedOutput: []TimelineEntry{
+				{Kind: TimelineEntryPosition, StartDate: date(2010, time.June), EndDate: date(2012, time.June), Label: "Consultant"},
+				{Kind: TimelineEntryEducation, StartDate: date(2010, time.June), EndDate: date(2011, time.June), Label: "Master"},
+				{Kind: TimelineEntryPosition, EndDate: date(2011, time.January), Label: "Teacher"},
+				{Kind: TimelineEntryPosition, Label: "Undated"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, tc.resume.Timeline())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package rps
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// timeoutError is a timeout, either on the client or the server side.
+type timeoutError struct {
+	message string
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *timeoutError) Error() string {
+	return e.message
+}
+
+// IsTimeout reports that the error is a timeout.
+func (e *timeoutError) IsTimeout() bool {
+	return true
+}
+
+var (
+	// ErrGatewayTimeout is returned along with the underlying error
+	// when the service, or a proxy in front of it, timed out (504).
+	ErrGatewayTimeout error = &timeoutError{message: "gateway timeout"}
+
+	// ErrClientTimeout is returned along with the underlying error
+	// when the request exceeded the deadline of the client.
+	ErrClientTimeout error = &timeoutError{message: "client timeout"}
+
+	// ErrDecodeTimeout is returned when decoding the response took
+	// longer than the timeout set through WithDecodeTimeout.
+	ErrDecodeTimeout error = &timeoutError{message: "decode timeout"}
+)
+
+// classifiedError joins a sentinel error, e.g. ErrGatewayTimeout, to the
+// underlying error, so that errors.Is matches either of them.
+type classifiedError struct {
+	sentinel error
+	err      error
+}
+
+// Error returns the error message. It implements the error interface.
+func (e *classifiedError) Error() string {
+	return e.sentinel.Error() + ": " + e.err.Error()
+}
+
+// Unwrap returns both the sentinel and the underlying error.
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// IsTimeout reports whether the error is a timeout, either
+// ErrGatewayTimeout, ErrClientTimeout or ErrDecodeTimeout.
+func IsTimeout(err error) bool {
+	var timeout interface{ IsTimeout() bool }
+	return errors.As(err, &timeout) && timeout.IsTimeout()
+}
+
+// classifyTimeout joins ErrGatewayTimeout or ErrClientTimeout
+// to the error of a request that timed out.
+func classifyTimeout(err error, statusCode int) error {
+	var netErr net.Error
+	switch {
+	case statusCode == http.StatusGatewayTimeout:
+		return &classifiedError{sentinel: ErrGatewayTimeout, err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &classifiedError{sentinel: ErrClientTimeout, err: err}
+	default:
+		return err
+	}
+}
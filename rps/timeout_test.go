@@ -0,0 +1,97 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TalentInc/resume-parsing-service-client/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentTimeouts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		delay         time.Duration
+		expectedError error
+	}{
+		{
+			name:       "no timeout",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:          "gateway timeout",
+			statusCode:    http.StatusGatewayTimeout,
+			expectedError: ErrGatewayTimeout,
+		},
+		{
+			name:          "client timeout",
+			statusCode:    http.StatusOK,
+			delay:         time.Second,
+			expectedError: ErrClientTimeout,
+		},
+		{
+			name:       "not a timeout",
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tc.delay):
+				case <-r.Context().Done():
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer svr.Close()
+			ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+			defer cancel()
+			client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+			_, err := client.ParseDocument(ctx, []byte{})
+			if tc.expectedError == nil {
+				require.False(t, IsTimeout(err))
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedError)
+			require.True(t, IsTimeout(err))
+			require.False(t, errors.Is(err, ErrGatewayTimeout) && errors.Is(err, ErrClientTimeout))
+		})
+	}
+}
+
+func TestClientTimeoutKeepsUnderlyingError(t *testing.T) {
+	err := classifyTimeout(context.DeadlineExceeded, 0)
+	require.ErrorIs(t, err, ErrClientTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, "client timeout: context deadline exceeded", err.Error())
+}
+
+func TestParseDocumentWithRequestTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The closing of the connection is only noticed
+		// once the request body was read.
+		_, _ = io.ReadAll(r.Body)
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL, WithRequestTimeout(100*time.Millisecond))
+	_, err := client.ParseDocument(context.TODO(), []byte{})
+	require.ErrorIs(t, err, ErrClientTimeout)
+	require.ErrorIs(t, err, httpclient.ErrRequestTimeout)
+	require.Contains(t, err.Error(), "request timed out after 100ms")
+}
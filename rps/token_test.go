@@ -0,0 +1,54 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tenantTokenKey struct{}
+
+func TestParseDocumentWithContextToken(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []Option
+		ctx           context.Context
+		expectedToken string
+	}{
+		{
+			name:          "static token by default",
+			ctx:           context.WithValue(context.TODO(), tenantTokenKey{}, "TENANT_TOKEN"),
+			expectedToken: "TOKEN",
+		},
+		{
+			name:          "context token overrides the static one",
+			options:       []Option{WithContextToken(tenantTokenKey{})},
+			ctx:           context.WithValue(context.TODO(), tenantTokenKey{}, "TENANT_TOKEN"),
+			expectedToken: "TENANT_TOKEN",
+		},
+		{
+			name:          "fallback to the static token when absent",
+			options:       []Option{WithContextToken(tenantTokenKey{})},
+			ctx:           context.TODO(),
+			expectedToken: "TOKEN",
+		},
+		{
+			name:          "fallback to the static token when not a string",
+			options:       []Option{WithContextToken(tenantTokenKey{})},
+			ctx:           context.WithValue(context.TODO(), tenantTokenKey{}, 42),
+			expectedToken: "TOKEN",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			_, err := client.ParseDocument(tc.ctx, []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedToken, mock.Req.Header.Get("token"))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package rps
+
+import (
+	"sort"
+	"time"
+)
+
+// moreRecent reports whether a start date is more recent than
+// another one. Missing start dates are the least recent.
+func moreRecent(date, another *time.Time) bool {
+	switch {
+	case date == nil:
+		return false
+	case another == nil:
+		return true
+	default:
+		return date.After(*another)
+	}
+}
+
+// truncatePositions sorts the positions by start date in descending
+// order and keeps the n most recent ones.
+func truncatePositions(resume *Resume, n int) {
+	sort.SliceStable(resume.Positions, func(i, j int) bool {
+		return moreRecent(resume.Positions[i].StartDate, resume.Positions[j].StartDate)
+	})
+	if n >= 0 && len(resume.Positions) > n {
+		resume.Positions = resume.Positions[:n]
+	}
+}
+
+// truncateEducations sorts the educations by start date in descending
+// order and keeps the n most recent ones.
+func truncateEducations(resume *Resume, n int) {
+	sort.SliceStable(resume.Educations, func(i, j int) bool {
+		return moreRecent(resume.Educations[i].StartDate, resume.Educations[j].StartDate)
+	})
+	if n >= 0 && len(resume.Educations) > n {
+		resume.Educations = resume.Educations[:n]
+	}
+}
@@ -0,0 +1,82 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithMaxPositionsAndEducations(t *testing.T) {
+	testCases := []struct {
+		name               string
+		options            []Option
+		expectedPositions  []Position
+		expectedEducations []Education
+	}{
+		{
+			name: "no truncation by default",
+			expectedPositions: []Position{
+				{Title: "a", StartDate: date(2015, 1)},
+				{Title: "b"},
+				{Title: "c", StartDate: date(2020, 1)},
+				{Title: "d", StartDate: date(2018, 1)},
+			},
+			expectedEducations: []Education{
+				{Degree: "e", StartDate: date(2010, 9)},
+				{Degree: "f", StartDate: date(2014, 9)},
+			},
+		},
+		{
+			name:    "most recent entries are kept",
+			options: []Option{WithMaxPositions(2), WithMaxEducations(1)},
+			expectedPositions: []Position{
+				{Title: "c", StartDate: date(2020, 1)},
+				{Title: "d", StartDate: date(2018, 1)},
+			},
+			expectedEducations: []Education{
+				{Degree: "f", StartDate: date(2014, 9)},
+			},
+		},
+		{
+			name:    "entries are sorted under the cap, undated last",
+			options: []Option{WithMaxPositions(10), WithMaxEducations(10)},
+			expectedPositions: []Position{
+				{Title: "c", StartDate: date(2020, 1)},
+				{Title: "d", StartDate: date(2018, 1)},
+				{Title: "a", StartDate: date(2015, 1)},
+				{Title: "b"},
+			},
+			expectedEducations: []Education{
+				{Degree: "f", StartDate: date(2014, 9)},
+				{Degree: "e", StartDate: date(2010, 9)},
+			},
+		},
+		{
+			name:               "zero drops every entry",
+			options:            []Option{WithMaxPositions(0), WithMaxEducations(0)},
+			expectedPositions:  []Position{},
+			expectedEducations: []Education{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				Positions: []Position{
+					{Title: "a", StartDate: date(2015, 1)},
+					{Title: "b"},
+					{Title: "c", StartDate: date(2020, 1)},
+					{Title: "d", StartDate: date(2018, 1)},
+				},
+				Educations: []Education{
+					{Degree: "e", StartDate: date(2010, 9)},
+					{Degree: "f", StartDate: date(2014, 9)},
+				},
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedPositions, resume.Positions)
+			require.Equal(t, tc.expectedEducations, resume.Educations)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package rps
+
+import "strings"
+
+// usStateNames maps the two-letter codes of the US states, the
+// District of Columbia and the inhabited territories to their names.
+var usStateNames = map[string]string{
+	"AL": "Alabama",
+	"AK": "Alaska",
+	"AZ": "Arizona",
+	"AR": "Arkansas",
+	"CA": "California",
+	"CO": "Colorado",
+	"CT": "Connecticut",
+	"DE": "Delaware",
+	"DC": "District of Columbia",
+	"FL": "Florida",
+	"GA": "Georgia",
+	"HI": "Hawaii",
+	"ID": "Idaho",
+	"IL": "Illinois",
+	"IN": "Indiana",
+	"IA": "Iowa",
+	"KS": "Kansas",
+	"KY": "Kentucky",
+	"LA": "Louisiana",
+	"ME": "Maine",
+	"MD": "Maryland",
+	"MA": "Massachusetts",
+	"MI": "Michigan",
+	"MN": "Minnesota",
+	"MS": "Mississippi",
+	"MO": "Missouri",
+	"MT": "Montana",
+	"NE": "Nebraska",
+	"NV": "Nevada",
+	"NH": "New Hampshire",
+	"NJ": "New Jersey",
+	"NM": "New Mexico",
+	"NY": "New York",
+	"NC": "North Carolina",
+	"ND": "North Dakota",
+	"OH": "Ohio",
+	"OK": "Oklahoma",
+	"OR": "Oregon",
+	"PA": "Pennsylvania",
+	"RI": "Rhode Island",
+	"SC": "South Carolina",
+	"SD": "South Dakota",
+	"TN": "Tennessee",
+	"TX": "Texas",
+	"UT": "Utah",
+	"VT": "Vermont",
+	"VA": "Virginia",
+	"WA": "Washington",
+	"WV": "West Virginia",
+	"WI": "Wisconsin",
+	"WY": "Wyoming",
+	"AS": "American Samoa",
+	"GU": "Guam",
+	"MP": "Northern Mariana Islands",
+	"PR": "Puerto Rico",
+	"VI": "U.S. Virgin Islands",
+}
+
+// usStateCodes maps the lowercase names of the US states to their codes.
+var usStateCodes = func() map[string]string {
+	codes := make(map[string]string, len(usStateNames))
+	for code, name := range usStateNames {
+		codes[strings.ToLower(name)] = code
+	}
+	return codes
+}()
+
+// USStateCode returns the two-letter code of the given US state, e.g.
+// "PA" for "Pennsylvania", whether given by name, case-insensitively,
+// or already by code. False is returned for anything else.
+func USStateCode(state string) (string, bool) {
+	state = strings.TrimSpace(state)
+	if code := strings.ToUpper(state); usStateNames[code] != "" {
+		return code, true
+	}
+	code, ok := usStateCodes[strings.ToLower(state)]
+	return code, ok
+}
+
+// USStateName returns the name of the US state of the given two-letter
+// code, case-insensitively, e.g. "Pennsylvania" for "PA".
+func USStateName(code string) (string, bool) {
+	name, ok := usStateNames[strings.ToUpper(strings.TrimSpace(code))]
+	return name, ok
+}
+
+// isUS reports whether the location is in the United States, going by
+// its country code, or its country name if there is no code.
+func (l *Location) isUS() bool {
+	if l.CountryCode != "" {
+		return strings.EqualFold(l.CountryCode, "US")
+	}
+	switch strings.ToLower(strings.TrimSpace(l.Country)) {
+	case "united states", "united states of america", "usa", "us":
+		return true
+	}
+	return false
+}
+
+// normalizeUSState replaces the state of a US location with its
+// two-letter code. Other locations and unknown states are untouched.
+func normalizeUSState(location *Location) {
+	if !location.isUS() {
+		return
+	}
+	if code, ok := USStateCode(location.State); ok {
+		location.State = code
+	}
+}
+
+// normalizeResumeUSStates normalizes the states of the locations of the
+// resume, its positions and its educations.
+func normalizeResumeUSStates(resume *Resume) {
+	normalizeUSState(&resume.Location)
+	for i := range resume.Positions {
+		normalizeUSState(&resume.Positions[i].Location)
+	}
+	for i := range resume.Educations {
+		normalizeUSState(&resume.Educations[i].Location)
+	}
+}
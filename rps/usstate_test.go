@@ -0,0 +1,110 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSStateCode(t *testing.T) {
+	testCases := []struct {
+		state         string
+		expectedCode  string
+		expectedFound bool
+	}{
+		{state: "Pennsylvania", expectedCode: "PA", expectedFound: true},
+		{state: " new york ", expectedCode: "NY", expectedFound: true},
+		{state: "District of Columbia", expectedCode: "DC", expectedFound: true},
+		{state: "PA", expectedCode: "PA", expectedFound: true},
+		{state: "ca", expectedCode: "CA", expectedFound: true},
+		{state: "Verona"},
+		{state: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.state, func(t *testing.T) {
+			code, found := USStateCode(tc.state)
+			require.Equal(t, tc.expectedCode, code)
+			require.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
+
+func TestUSStateName(t *testing.T) {
+	testCases := []struct {
+		code          string
+		expectedName  string
+		expectedFound bool
+	}{
+		{code: "PA", expectedName: "Pennsylvania", expectedFound: true},
+		{code: "ny", expectedName: "New York", expectedFound: true},
+		{code: "VR"},
+		{code: "Pennsylvania"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			name, found := USStateName(tc.code)
+			require.Equal(t, tc.expectedName, name)
+			require.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
+
+func TestParseDocumentWithUSStateNormalization(t *testing.T) {
+	testCases := []struct {
+		name          string
+		location      Location
+		expectedState string
+	}{
+		{
+			name:          "full name",
+			location:      Location{City: "Philadelphia", State: "Pennsylvania", Country: "United States", CountryCode: "US"},
+			expectedState: "PA",
+		},
+		{
+			name:          "abbreviation",
+			location:      Location{City: "Philadelphia", State: "pa", CountryCode: "US"},
+			expectedState: "PA",
+		},
+		{
+			name:          "country name without code",
+			location:      Location{City: "Albany", State: "New York", Country: "USA"},
+			expectedState: "NY",
+		},
+		{
+			name:          "unknown US state",
+			location:      Location{City: "Philadelphia", State: "Philly County", CountryCode: "US"},
+			expectedState: "Philly County",
+		},
+		{
+			name:          "non-US state",
+			location:      Location{City: "Verona", State: "Verona", Country: "Italy", CountryCode: "IT"},
+			expectedState: "Verona",
+		},
+		{
+			name:          "non-US state named like a US one",
+			location:      Location{City: "Tbilisi", State: "Georgia", CountryCode: "GE"},
+			expectedState: "Georgia",
+		},
+		{
+			name:          "unknown country",
+			location:      Location{State: "Pennsylvania"},
+			expectedState: "Pennsylvania",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				Location:   tc.location,
+				Positions:  []Position{{Location: tc.location}},
+				Educations: []Education{{Location: tc.location}},
+			}, WithUSStateNormalization())
+
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedState, resume.Location.State)
+			require.Equal(t, tc.expectedState, resume.Positions[0].Location.State)
+			require.Equal(t, tc.expectedState, resume.Educations[0].Location.State)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package rps
+
+import "github.com/pkg/errors"
+
+// validateDocuments runs the request validator set through
+// WithRequestValidator on each document before it is encoded.
+// A single document's error is returned as is, so that callers
+// get back exactly what their validator returned.
+func (r *resumeParsingServiceClient) validateDocuments(docs ...[]byte) error {
+	if r.requestValidator == nil {
+		return nil
+	}
+	if len(docs) == 1 {
+		return r.requestValidator(docs[0])
+	}
+	for i, doc := range docs {
+		if err := r.requestValidator(doc); err != nil {
+			return errors.Wrapf(err, "validating document %d", i)
+		}
+	}
+	return nil
+}
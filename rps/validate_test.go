@@ -0,0 +1,72 @@
+package rps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errMissingMagicBytes = errors.New("missing PDF magic bytes")
+
+func requirePDF(fileContents []byte) error {
+	if !bytes.HasPrefix(fileContents, []byte("%PDF-")) {
+		return errMissingMagicBytes
+	}
+	return nil
+}
+
+func TestParseDocumentWithRequestValidator(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []Option
+		fileContents  []byte
+		expectedError error
+	}{
+		{
+			name:         "no validator by default",
+			fileContents: []byte("plain text"),
+		},
+		{
+			name:         "validator passes",
+			options:      []Option{WithRequestValidator(requirePDF)},
+			fileContents: []byte("%PDF-1.7"),
+		},
+		{
+			name:          "validator rejects",
+			options:       []Option{WithRequestValidator(requirePDF)},
+			fileContents:  []byte("plain text"),
+			expectedError: errMissingMagicBytes,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			_, err := client.ParseDocument(context.TODO(), tc.fileContents)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err)
+				require.Nil(t, mock.Req)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.NotNil(t, mock.Req)
+			}
+		})
+	}
+}
+
+func TestParseDocumentBundleWithRequestValidator(t *testing.T) {
+	client := NewResumeParsingServiceClient("TOKEN", "URL", WithRequestValidator(requirePDF))
+	_, err := client.ParseDocumentBundle(context.TODO(), [][]byte{[]byte("%PDF-1.7"), []byte("plain text")})
+	require.Equal(t, "validating document 1: missing PDF magic bytes", err.Error())
+	require.ErrorIs(t, err, errMissingMagicBytes)
+}
@@ -0,0 +1,64 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentWithServiceVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		options         []Option
+		expectedVersion string
+	}{
+		{
+			name: "no version header by default",
+		},
+		{
+			name:            "version header",
+			options:         []Option{WithServiceVersion("2024-01-01")},
+			expectedVersion: "2024-01-01",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedVersion, mock.Req.Header.Get("Accept-Version"))
+		})
+	}
+}
+
+func TestParseDocumentWithUserAgent(t *testing.T) {
+	testCases := []struct {
+		name              string
+		options           []Option
+		expectedUserAgent string
+	}{
+		{
+			name: "default user agent",
+		},
+		{
+			name:              "user agent",
+			options:           []Option{WithUserAgent("candidate-import/1.4")},
+			expectedUserAgent: "candidate-import/1.4",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, nil, tc.options...)
+			mock, ok := client.httpClient.(*resumeHttpClientMock)
+			require.True(t, ok)
+
+			_, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedUserAgent, mock.Req.Header.Get("User-Agent"))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package rps
+
+import "github.com/pkg/errors"
+
+// ErrUnusableResume is returned when the parsed resume is not viable
+// according to the check set through WithMinimumViableResume.
+var ErrUnusableResume = errors.New("unusable resume")
+
+// checkViable returns ErrUnusableResume when the resume fails the
+// check set through WithMinimumViableResume, or an error holding the
+// panic of the check, if it panics.
+func (r *resumeParsingServiceClient) checkViable(resume *Resume) (err error) {
+	if r.viableResume == nil {
+		return nil
+	}
+	defer r.recoverHookError("minimum viable resume check", &err)
+	if !r.viableResume(resume) {
+		return ErrUnusableResume
+	}
+	return nil
+}
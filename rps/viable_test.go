@@ -0,0 +1,78 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func hasHistory(resume *Resume) bool {
+	return len(resume.Positions) > 0 || len(resume.Educations) > 0
+}
+
+func TestParseDocumentWithMinimumViableResume(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []Option
+		resume        *Resume
+		expectedError error
+	}{
+		{
+			name:   "no check by default",
+			resume: &Resume{FirstName: "Morgana"},
+		},
+		{
+			name:    "viable resume",
+			options: []Option{WithMinimumViableResume(hasHistory)},
+			resume:  &Resume{FirstName: "Morgana", Educations: []Education{{Degree: "Doctor of Philosophy"}}},
+		},
+		{
+			name:          "non-viable resume",
+			options:       []Option{WithMinimumViableResume(hasHistory)},
+			resume:        &Resume{FirstName: "Morgana"},
+			expectedError: ErrUnusableResume,
+		},
+		{
+			name: "checked once normalized",
+			options: []Option{
+				WithMinEntryConfidence(0.5),
+				WithMinimumViableResume(hasHistory),
+			},
+			resume:        &Resume{FirstName: "Morgana", Positions: []Position{{Title: "Researcher", Confidence: 0.1}}},
+			expectedError: ErrUnusableResume,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, tc.resume, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.ErrorIs(t, err, tc.expectedError)
+				require.Nil(t, resume)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError.Error())
+				}
+				require.Equal(t, "Morgana", resume.FirstName)
+			}
+		})
+	}
+}
+
+func TestParseDocumentBundleWithMinimumViableResume(t *testing.T) {
+	client := newResumeParsingServiceClient([]Option{WithMinimumViableResume(hasHistory)})
+	client.httpClient = &staticHttpClient{body: []byte(`[
+		{"resume": {"first_name": "Morgana", "positions": [{"title": "Researcher"}]}},
+		{"resume": {"first_name": "Morgana"}}
+	]`)}
+	resumes, err := client.ParseDocumentBundle(context.TODO(), [][]byte{{}, {}})
+	var bundleErr *BundleError
+	require.ErrorAs(t, err, &bundleErr)
+	require.NotNil(t, resumes[0])
+	require.Nil(t, resumes[1])
+	require.ErrorIs(t, bundleErr.Errors[1], ErrUnusableResume)
+}
@@ -0,0 +1,54 @@
+package rps
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/TalentInc/resume-parsing-service-client/httpclient"
+	"github.com/pkg/errors"
+)
+
+// Warmup opens n connections to the service through concurrent HEAD
+// requests to its base URL, so that the connection pool is primed and
+// the first parses do not pay for TLS handshakes. Any response, even an
+// unsuccessful one, means the connection was established. The first
+// error is returned, e.g. when the context is canceled. Nothing is done
+// when n is not positive.
+func (r *resumeParsingServiceClient) Warmup(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- r.warmupConnection(ctx)
+		}()
+	}
+	var firstErr error
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// warmupConnection sends a HEAD request to the base URL of the service.
+func (r *resumeParsingServiceClient) warmupConnection(ctx context.Context) error {
+	req, err := newRequestWithContext(ctx, http.MethodHead, r.rioParseBaseUrl, nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	resp, err := r.httpClient.SendRequest(req)
+	var httpErr *httpclient.HttpError
+	if errors.As(err, &httpErr) && httpErr.StatusCode != 0 {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "warming up connection")
+	}
+	return resp.Body.Close()
+}
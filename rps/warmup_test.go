@@ -0,0 +1,93 @@
+package rps
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmup(t *testing.T) {
+	const n = 3
+	var mu sync.Mutex
+	newConnCount := 0
+	var warmupPaths []string
+	// Holds the warmup requests until all of them arrived, so
+	// that none of the connections is reused by another one.
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			mu.Lock()
+			warmupPaths = append(warmupPaths, r.URL.Path)
+			mu.Unlock()
+			arrived.Done()
+			arrived.Wait()
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	svr.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			newConnCount++
+			mu.Unlock()
+		}
+	}
+	svr.Start()
+	defer svr.Close()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL+"/")
+
+	err := client.Warmup(context.TODO(), n)
+	require.Nil(t, err)
+	mu.Lock()
+	require.Equal(t, n, newConnCount)
+	require.Equal(t, []string{"/", "/", "/"}, warmupPaths)
+	mu.Unlock()
+
+	var reused bool
+	ctx := httptrace.WithClientTrace(context.TODO(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	})
+	_, err = client.ParseDocument(ctx, []byte{})
+	require.Nil(t, err)
+	require.True(t, reused)
+}
+
+func TestWarmupWithCanceledContext(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer svr.Close()
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+
+	err := client.Warmup(ctx, 2)
+	require.NotNil(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestWarmupWithoutConnections(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		var requests atomic.Int32
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+		}))
+		client := NewResumeParsingServiceClient("TOKEN", svr.URL)
+
+		err := client.Warmup(context.TODO(), n)
+		svr.Close()
+		require.Nil(t, err)
+		require.Zero(t, requests.Load())
+	}
+}
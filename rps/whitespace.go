@@ -0,0 +1,48 @@
+package rps
+
+import "strings"
+
+// TextField identifies a free-text field of the resume.
+type TextField string
+
+// Text fields whose whitespace can be normalized.
+const (
+	TextFieldSummary     TextField = "summary"
+	TextFieldRawText     TextField = "raw_text"
+	TextFieldDescription TextField = "description"
+)
+
+// textFieldMappers apply a function to the given text field of a resume.
+var textFieldMappers = map[TextField]func(resume *Resume, f func(string) string){
+	TextFieldSummary: func(resume *Resume, f func(string) string) {
+		resume.Summary = f(resume.Summary)
+	},
+	TextFieldRawText: func(resume *Resume, f func(string) string) {
+		resume.RawText = f(resume.RawText)
+	},
+	TextFieldDescription: func(resume *Resume, f func(string) string) {
+		for i := range resume.Positions {
+			resume.Positions[i].Description = f(resume.Positions[i].Description)
+		}
+	},
+}
+
+// normalizeWhitespace collapses runs of whitespace, including
+// tabs and newlines, into single spaces and trims s.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeResumeWhitespace normalizes the whitespace of the given
+// text fields or, if none is given, of every string field.
+func normalizeResumeWhitespace(resume *Resume, fields []TextField) {
+	if len(fields) == 0 {
+		mapStrings(resume, normalizeWhitespace)
+		return
+	}
+	for _, field := range fields {
+		if mapper, ok := textFieldMappers[field]; ok {
+			mapper(resume, normalizeWhitespace)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "already normalized",
+			input:          "I am a Neuroscientist",
+			expectedOutput: "I am a Neuroscientist",
+		},
+		{
+			name:           "doubled spaces and tabs",
+			input:          " I  am\ta \t Neuroscientist ",
+			expectedOutput: "I am a Neuroscientist",
+		},
+		{
+			name:           "multiline",
+			input:          "MORGANA FAVERO\r\n\r\n3850 Woodhaven Road\nPhiladelphia\n",
+			expectedOutput: "MORGANA FAVERO 3850 Woodhaven Road Philadelphia",
+		},
+		{
+			name:  "whitespace only",
+			input: " \n\t ",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, normalizeWhitespace(tc.input))
+		})
+	}
+}
+
+func TestParseDocumentWithNormalizeWhitespace(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        []Option
+		expectedOutput *Resume
+	}{
+		{
+			name: "whitespace is kept by default",
+			expectedOutput: &Resume{
+				FirstName: " Morgana ",
+				Summary:   "I am a\tNeuroscientist",
+				Positions: []Position{{Title: "Postdoctoral  Researcher", Description: "Research\n\nTeaching"}},
+				RawText:   "MORGANA FAVERO\n3850 Woodhaven Road",
+			},
+		},
+		{
+			name:    "every string field",
+			options: []Option{WithNormalizeWhitespace()},
+			expectedOutput: &Resume{
+				FirstName: "Morgana",
+				Summary:   "I am a Neuroscientist",
+				Positions: []Position{{Title: "Postdoctoral Researcher", Description: "Research Teaching"}},
+				RawText:   "MORGANA FAVERO 3850 Woodhaven Road",
+			},
+		},
+		{
+			name:    "selected fields",
+			options: []Option{WithNormalizeWhitespace(TextFieldSummary, TextFieldDescription)},
+			expectedOutput: &Resume{
+				FirstName: " Morgana ",
+				Summary:   "I am a Neuroscientist",
+				Positions: []Position{{Title: "Postdoctoral  Researcher", Description: "Research Teaching"}},
+				RawText:   "MORGANA FAVERO\n3850 Woodhaven Road",
+			},
+		},
+		{
+			name:    "raw text",
+			options: []Option{WithNormalizeWhitespace(TextFieldRawText, TextField("unknown"))},
+			expectedOutput: &Resume{
+				FirstName: " Morgana ",
+				Summary:   "I am a\tNeuroscientist",
+				Positions: []Position{{Title: "Postdoctoral  Researcher", Description: "Research\n\nTeaching"}},
+				RawText:   "MORGANA FAVERO 3850 Woodhaven Road",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClientWithMock(t, &Resume{
+				FirstName: " Morgana ",
+				Summary:   "I am a\tNeuroscientist",
+				Positions: []Position{{Title: "Postdoctoral  Researcher", Description: "Research\n\nTeaching"}},
+				RawText:   "MORGANA FAVERO\n3850 Woodhaven Road",
+			}, tc.options...)
+			resume, err := client.ParseDocument(context.TODO(), []byte{})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedOutput, resume)
+		})
+	}
+}